@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/KyleBanks/kurz/pkg/config"
+	"github.com/KyleBanks/kurz/pkg/doc/resolver"
+	"github.com/KyleBanks/kurz/pkg/ui/console"
+)
+
+// textStyles contains the valid characters of a style's TextStyle.
+const textStyles = "bdlru"
+
+// applyStyles overrides the console.StyleMap with the configured styles.
+func applyStyles(c config.Config) error {
+	for name, s := range c.Styles {
+		ds, ok := console.StyleNames[strings.ToLower(name)]
+		if !ok {
+			return fmt.Errorf("unknown style '%v'", name)
+		}
+		if s.TextStyle != nil && strings.Trim(*s.TextStyle, textStyles) != "" {
+			return fmt.Errorf("invalid text style '%v' for %v, expected any of '%v'", *s.TextStyle, name, textStyles)
+		}
+
+		console.StyleMap[ds] = applyStyle(s, console.StyleMap[ds])
+	}
+	return nil
+}
+
+// applyStyle returns the console.Style with the configured fields overridden.
+func applyStyle(s config.Style, cs console.Style) console.Style {
+	if s.FgColor != nil {
+		cs.FgColor = *s.FgColor
+	}
+	if s.BgColor != nil {
+		cs.BgColor = *s.BgColor
+	}
+	if s.TextStyle != nil {
+		cs.TextStyle = *s.TextStyle
+	}
+	if s.Indent != nil {
+		cs.Indent = *s.Indent
+	}
+	return cs
+}
+
+// applyHosts adds the configured Git servers to the resolver.
+func applyHosts(c config.Config) error {
+	for name, h := range c.Hosts {
+		if err := resolver.AddHost(name, resolver.Host(h)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyKeys binds the configured preset and keys to the window's inputs.
+func applyKeys(c config.Config, w *console.Window) error {
+	if c.Preset != "" {
+		if err := w.SetKeyPreset(c.Preset); err != nil {
+			return err
+		}
+	}
+	if len(c.Keys) == 0 {
+		return nil
+	}
+	return w.SetKeyBindings(c.Keys)
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/KyleBanks/kurz/pkg/config"
+	"github.com/KyleBanks/kurz/pkg/ui/console"
+)
+
+var (
+	path      string
+	offline   bool
+	printMode bool
+	watch     bool
+	chapters  bool
+	theme     string
+	width     int
+	heading   string
+	logFile   string
+	noColor   bool
+	cfgPath   string
+	credsPath string
+	keys      string
+	timeout   time.Duration
+
+	// command is the subcommand provided before the path, if any.
+	command string
+	format  string
+	output  string
+)
+
+// exportCommand writes the document to a file in another format.
+const exportCommand = "export"
+
+// exportFormats contains the formats supported by the export command,
+// the first of which is the default.
+var exportFormats = []string{"html", "json"}
+
+const (
+	// defaultTheme is the theme used when none is provided.
+	defaultTheme = "dark"
+
+	// defaultTimeout is the time allowed to load a document when no
+	// timeout is provided.
+	defaultTimeout = time.Second * 30
+)
+
+// parseFlags parses the command-line arguments into the options, command
+// and path. Each option defaults to the value of its environment variable,
+// if set, and flags may appear before or after the command and path.
+func parseFlags(args []string) error {
+	fs := flag.NewFlagSet("kurz", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+
+	var env envDefaults
+	fs.BoolVar(&offline, "offline", env.bool("KURZ_OFFLINE"), "")
+	fs.BoolVar(&printMode, "print", env.bool("KURZ_PRINT"), "")
+	fs.BoolVar(&watch, "watch", env.bool("KURZ_WATCH"), "")
+	fs.BoolVar(&chapters, "chapters", env.bool("KURZ_CHAPTERS"), "")
+	fs.StringVar(&theme, "theme", env.string("KURZ_THEME", defaultTheme), "")
+	fs.IntVar(&width, "width", env.int("KURZ_WIDTH"), "")
+	fs.StringVar(&heading, "heading", env.string("KURZ_HEADING", ""), "")
+	fs.StringVar(&logFile, "log", env.string("KURZ_LOG", ""), "")
+	fs.DurationVar(&timeout, "timeout", env.duration("KURZ_TIMEOUT", defaultTimeout), "")
+	fs.StringVar(&keys, "keys", env.string("KURZ_KEYS", ""), "")
+	fs.StringVar(&cfgPath, "config", config.Path(), "")
+	fs.StringVar(&credsPath, "credentials", config.CredentialsPath(), "")
+	fs.BoolVar(&noColor, "no-color", env.bool("KURZ_NO_COLOR") || os.Getenv("NO_COLOR") != "", "")
+	fs.StringVar(&format, "format", "", "")
+	fs.StringVar(&output, "output", "", "")
+	if env.err != nil {
+		return env.err
+	}
+
+	for {
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		if args[0] == exportCommand && command == "" && path == "" {
+			command, args = args[0], args[1:]
+			continue
+		}
+		if path != "" {
+			return fmt.Errorf("unexpected argument '%v'", args[0])
+		}
+		path, args = args[0], args[1:]
+	}
+
+	if _, ok := console.Presets[keys]; keys != "" && !ok {
+		return fmt.Errorf("unknown key preset '%v'", keys)
+	}
+	if timeout < 0 {
+		return fmt.Errorf("invalid timeout %v, must not be negative", timeout)
+	}
+	// Providing a format implies the export command.
+	if format != "" {
+		command = exportCommand
+	} else if command == exportCommand {
+		format = exportFormats[0]
+	}
+	if format != "" && !contains(exportFormats, format) {
+		return fmt.Errorf("unknown export format '%v'", format)
+	}
+	if width < 0 {
+		return fmt.Errorf("invalid width %v, must not be negative", width)
+	}
+	return nil
+}
+
+// envDefaults reads the default values of options from environment
+// variables, retaining the first invalid value that is found.
+type envDefaults struct {
+	err error
+}
+
+func (e *envDefaults) string(name, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return def
+}
+
+func (e *envDefaults) bool(name string) bool {
+	v := os.Getenv(name)
+	if v == "" {
+		return false
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil && e.err == nil {
+		e.err = fmt.Errorf("invalid value '%v' for %v, expected true or false", v, name)
+	}
+	return b
+}
+
+func (e *envDefaults) int(name string) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return 0
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil && e.err == nil {
+		e.err = fmt.Errorf("invalid value '%v' for %v, expected a number", v, name)
+	}
+	return i
+}
+
+func (e *envDefaults) duration(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil && e.err == nil {
+		e.err = fmt.Errorf("invalid value '%v' for %v, expected a duration such as 10s", v, name)
+	}
+	return d
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -3,7 +3,11 @@ package main
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+
+	"github.com/KyleBanks/kurz/pkg/config"
+	"github.com/KyleBanks/kurz/pkg/ui/console"
 )
 
 func log(msg string, a ...interface{}) {
@@ -18,6 +22,12 @@ func logError(err error) {
 	os.Exit(1)
 }
 
+// usageError prints the error followed by the usage message, and exits.
+func usageError(err error) {
+	log("ERROR: %v\n", err)
+	printUsage(1)
+}
+
 func printUsage(code int) {
 	name := os.Args[0]
 
@@ -25,13 +35,91 @@ func printUsage(code int) {
 
 Usage:
   %v [options] path 
-    	Where 'path' is a local file, remote URL, or Git repository.
+    	Where 'path' is a local file or directory, remote URL, or Git repository.
+    	Use '-' or pipe a document to read it from stdin.
+  %v export [options] path
+    	Write the document to a standalone file in the provided --format.
+
+Options:
+  --chapters
+    	Display each header along with the headers nested under it, which can
+    	also be toggled with 'v' in the interactive UI.
+    	Environment: KURZ_CHAPTERS
+  --config file
+    	The configuration file customizing styles, key bindings and Git servers.
+    	Environment: KURZ_CONFIG (default "%v")
+  --credentials file
+    	The tokens used to access private Git repositories, one URL per line
+    	such as https://TOKEN@github.com. Tokens may also be provided in the
+    	GITHUB_TOKEN, GITLAB_TOKEN and BITBUCKET_TOKEN environment variables.
+    	Environment: KURZ_CREDENTIALS (default "%v")
+  --format name
+    	The format written by the export command, one of %v. (default "%v")
+    	Providing a format implies the export command.
+  --heading name
+    	Open the document at the header with the provided title or anchor.
+    	Environment: KURZ_HEADING
+  --keys preset
+    	The key bindings, one of %v. (default "default")
+    	Environment: KURZ_KEYS
+  --log file
+    	Append debug logs to the file.
+    	Environment: KURZ_LOG
+  --no-color
+    	Disable colors, in both the interactive UI and printed output.
+    	Environment: KURZ_NO_COLOR or NO_COLOR
+  --offline
+    	Load remote files from the local cache without using the network.
+    	Environment: KURZ_OFFLINE
+  --output file
+    	The file written by the export command, instead of stdout.
+  --print
+    	Print the document to stdout instead of opening the interactive UI.
+    	Output is colored when stdout is a terminal, and wrapped to its width.
+    	Environment: KURZ_PRINT
+  --theme name
+    	The color theme, one of %v. (default "%v")
+    	Environment: KURZ_THEME
+  --timeout duration
+    	The time allowed to load a document, such as 10s, or 0 for no limit. (default %v)
+    	Environment: KURZ_TIMEOUT
+  --watch
+    	Reload a local file each time it is modified.
+    	Environment: KURZ_WATCH
+  --width columns
+    	The maximum width that documents are laid out to.
+    	Environment: KURZ_WIDTH
 
 Example:
   %v ./path/to/file.md
+  %v ./docs
+  %v --print --width 80 --heading usage README.md
+  %v export --format html --output kurz.html github.com/KyleBanks/kurz
+  %v --format json README.md
   %v http://example.com/document.md
   %v github.com/KyleBanks/modoc
+  git show HEAD:README.md | %v -
 
-To print this message, use the '--help' flag.`, name, name, name, name, name)
+To print this message, use the '--help' flag.`, name, name, name, config.Path(), config.CredentialsPath(), strings.Join(exportFormats, ", "), exportFormats[0], presetNames(), themeNames(), defaultTheme, defaultTimeout, name, name, name, name, name, name, name, name)
 	os.Exit(code)
 }
+
+// themeNames returns the names of the available themes.
+func themeNames() string {
+	var names []string
+	for n := range console.Themes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// presetNames returns the names of the available key binding presets.
+func presetNames() string {
+	var names []string
+	for n := range console.Presets {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
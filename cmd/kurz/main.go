@@ -1,67 +1,246 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/KyleBanks/kurz/pkg/config"
 	"github.com/KyleBanks/kurz/pkg/debug"
 	"github.com/KyleBanks/kurz/pkg/doc"
 	"github.com/KyleBanks/kurz/pkg/doc/parser"
 	"github.com/KyleBanks/kurz/pkg/doc/resolver"
 	"github.com/KyleBanks/kurz/pkg/ui"
 	"github.com/KyleBanks/kurz/pkg/ui/console"
+	"github.com/KyleBanks/kurz/pkg/ui/export"
+	"github.com/KyleBanks/kurz/pkg/ui/printer"
 )
 
-var path string
+var (
+	// cfg is the user's configuration, loaded from the config file.
+	cfg config.Config
+
+	// creds are the tokens used to access private Git repositories,
+	// loaded from the credentials file.
+	creds resolver.Credentials
+)
+
+// watchInterval is how often a watched file is checked for changes.
+const watchInterval = time.Millisecond * 500
 
 func init() {
-	if len(os.Args) < 2 {
-		printUsage(1)
+	if err := parseFlags(os.Args[1:]); err == flag.ErrHelp {
+		printUsage(0)
+	} else if err != nil {
+		usageError(err)
 	}
 
-	switch arg := os.Args[1]; arg {
+	// Read from stdin when it's piped and no other path is provided.
+	if path == "" && !printer.IsTerminal(os.Stdin) {
+		path = resolver.StdinPath
+	}
 
-	case "-h":
-		fallthrough
-	case "--help":
-		printUsage(0)
+	if path == "" {
+		printUsage(1)
+	}
 
-	default:
-		path = arg
+	if noColor {
+		theme = "mono"
+	}
+	if err := console.SetTheme(theme); err != nil {
+		usageError(err)
+	}
+
+	var err error
+	if cfg, err = config.Load(cfgPath); err != nil {
+		logError(err)
+	}
+	if err := applyHosts(cfg); err != nil {
+		logError(fmt.Errorf("invalid config %v: %v", cfgPath, err))
+	}
+	if creds, err = resolver.LoadCredentials(credsPath); err != nil {
+		logError(err)
+	}
+	if keys != "" {
+		cfg.Preset = keys
+	}
+	if !noColor {
+		if err := applyStyles(cfg); err != nil {
+			logError(fmt.Errorf("invalid config %v: %v", cfgPath, err))
+		}
 	}
 
 	debug.Enabled = os.Getenv("KURZ_DEBUG") == "true"
+	if logFile != "" {
+		f, err := os.OpenFile(logFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			logError(err)
+		}
+
+		debug.Out = f
+		debug.Enabled = true
+	}
 }
 
 func main() {
 	r := resolver.Chain{
 		Resolvers: []doc.Resolver{
+			&resolver.Stdin{Reader: os.Stdin},
 			resolver.File{},
-			resolver.URL{},
-			resolver.Git{},
+			resolver.Cache{
+				Resolver: resolver.Chain{
+					Resolvers: []doc.Resolver{
+						resolver.URL{},
+						resolver.Git{Credentials: creds},
+					},
+				},
+				Offline: offline,
+			},
 		},
 	}
-	p := parser.NewMarkdown(console.Styler{})
+	p := parser.NewMarkdown()
 
-	runWithConsole(r, p)
+	switch {
+	case command == exportCommand:
+		runExport(r, p)
+	case printMode:
+		runWithPrinter(r, p)
+	default:
+		runWithConsole(r, p)
+	}
 }
 
 func runWithConsole(r doc.Resolver, p doc.Parser) {
 	w := console.NewWindow()
-	w.ShowMessage(fmt.Sprintf("Loading %v...", path))
+	w.SetMaxWidth(width)
+	w.SetChapters(chapters)
+	if err := applyKeys(cfg, w); err != nil {
+		logError(fmt.Errorf("invalid config %v: %v", cfgPath, err))
+	}
+	w.SetTimeout(timeout)
+	w.SetLoader(func(ctx context.Context, path string) (doc.Document, error) {
+		return doc.NewDocument(ctx, path, r, p)
+	})
 
-	go render(w, path, r, p)
+	if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+		w.ShowMessage(fmt.Sprintf("Loading %v...", path))
+		w.Go(func() { browse(w, path) })
+	} else {
+		w.Open(path, heading)
+	}
+	if watch {
+		w.Go(func() { reload(w, path, r, p) })
+	}
 
 	if err := w.Run(); err != nil {
 		logError(err)
 	}
 }
 
-func render(c ui.Canvas, path string, r doc.Resolver, p doc.Parser) {
-	d, err := doc.NewDocument(path, r, p)
+func runWithPrinter(r doc.Resolver, p doc.Parser) {
+	d := loadDocument(r, p)
+
+	pr := printer.New(os.Stdout)
+	if width > 0 {
+		pr.Width = width
+	}
+	if noColor {
+		pr.Color = false
+	}
+	pr.RenderDocument(d)
+	if err := pr.Err(); err != nil {
+		logError(err)
+	}
+}
+
+func runExport(r doc.Resolver, p doc.Parser) {
+	d := loadDocument(r, p)
+
+	out := os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			logError(err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	var e interface {
+		ui.Canvas
+		Err() error
+	}
+	switch format {
+	case "json":
+		e = &export.JSON{Out: out}
+	default:
+		e = &export.HTML{Out: out, Dark: theme == defaultTheme}
+	}
+
+	e.RenderDocument(d)
+	if err := e.Err(); err != nil {
+		logError(err)
+	}
+}
+
+// loadDocument loads the document at the path within the timeout,
+// starting at the heading if one was provided.
+func loadDocument(r doc.Resolver, p doc.Parser) doc.Document {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	d, err := doc.NewDocument(ctx, path, r, p)
 	if err != nil {
 		logError(err)
 	}
 
-	c.RenderDocument(d)
+	if heading != "" {
+		idx := d.FindHeader(heading)
+		if idx < 0 {
+			logError(fmt.Errorf("no header found for '%v'", heading))
+		}
+		d.Headers = d.Headers[idx:]
+	}
+	return d
+}
+
+// browse renders a navigator of the markdown files within a directory.
+func browse(w *console.Window, dir string) {
+	files, err := resolver.MarkdownFiles(dir)
+	if err == nil && len(files) == 0 {
+		err = fmt.Errorf("no markdown files found in %v", dir)
+	}
+
+	w.QueueUpdateDraw(func() {
+		if err != nil {
+			w.ShowError(err, func() { w.Go(func() { browse(w, dir) }) })
+			return
+		}
+		w.RenderFiles(dir, files)
+	})
+}
+
+// reload renders the document again each time the local file at the
+// path is modified.
+func reload(w *console.Window, path string, r doc.Resolver, p doc.Parser) {
+	if fi, err := os.Stat(path); err != nil || fi.IsDir() {
+		debug.Log("Unable to watch %v: %v", path, err)
+		return
+	}
+
+	for range resolver.Watch(path, watchInterval, nil) {
+		d, err := doc.NewDocument(context.Background(), path, r, p)
+		if err != nil {
+			debug.Log("Failed to reload %v: %v", path, err)
+			continue
+		}
+
+		w.QueueUpdateDraw(func() { w.ReloadDocument(d) })
+	}
 }
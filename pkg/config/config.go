@@ -0,0 +1,116 @@
+// Package config loads the user's configuration file, which customizes
+// the styles and key bindings of the interactive UI, and the self-hosted
+// Git servers that documents are loaded from.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Config is the content of a configuration file, for example:
+//
+//	{
+//	  "preset": "vim",
+//	  "styles": {
+//	    "code": {"fg": "navy"},
+//	    "link": {"fg": "#005f87", "text": "u"}
+//	  },
+//	  "keys": {
+//	    "up": ["k", "Up"],
+//	    "down": ["j", "Down"]
+//	  },
+//	  "hosts": {
+//	    "git.corp.example.com": {"type": "gitlab"}
+//	  }
+//	}
+type Config struct {
+	// Preset is the name of the key binding preset that Keys are applied
+	// on top of. See console.Presets.
+	Preset string `json:"preset"`
+
+	// Styles overrides the style of each doc.Style by name.
+	// See console.StyleNames.
+	Styles map[string]Style `json:"styles"`
+
+	// Keys binds each action to a list of keys.
+	// See console.Actions and console.Window.SetKeyBindings.
+	Keys map[string][]string `json:"keys"`
+
+	// Hosts adds self-hosted Git servers by hostname.
+	// See resolver.AddHost.
+	Hosts map[string]Host `json:"hosts"`
+}
+
+// Style overrides the fields of a console.Style. Fields that are not
+// provided keep the value of the current theme.
+type Style struct {
+	FgColor   *string `json:"fg"`
+	BgColor   *string `json:"bg"`
+	TextStyle *string `json:"text"`
+	Indent    *string `json:"indent"`
+}
+
+// Host is a self-hosted Git server, see resolver.Host.
+type Host struct {
+	Type     string `json:"type"`
+	URL      string `json:"url,omitempty"`
+	Template string `json:"template,omitempty"`
+	API      string `json:"api,omitempty"`
+}
+
+// Path returns the location of the configuration file, which is the
+// KURZ_CONFIG environment variable if set, or kurz/config.json within
+// the user's configuration directory.
+func Path() string {
+	if p := os.Getenv("KURZ_CONFIG"); p != "" {
+		return p
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "kurz", "config.json")
+}
+
+// CredentialsPath returns the location of the credentials file, which is
+// the KURZ_CREDENTIALS environment variable if set, or kurz/credentials
+// within the user's configuration directory. See resolver.LoadCredentials.
+func CredentialsPath() string {
+	if p := os.Getenv("KURZ_CREDENTIALS"); p != "" {
+		return p
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "kurz", "credentials")
+}
+
+// Load reads the configuration file at the path. A missing file is not an
+// error, and results in an empty Config.
+func Load(path string) (Config, error) {
+	var c Config
+	if path == "" {
+		return c, nil
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	} else if err != nil {
+		return c, err
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&c); err != nil {
+		return c, fmt.Errorf("invalid config %v: %v", path, err)
+	}
+	return c, nil
+}
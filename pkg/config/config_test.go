@@ -0,0 +1,49 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kurz-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	valid := filepath.Join(dir, "valid.json")
+	ioutil.WriteFile(valid, []byte(`{"styles": {"code": {"fg": "navy"}}, "keys": {"up": ["k"]}, "hosts": {"git.example.com": {"type": "gitea"}}}`), 0644)
+	invalid := filepath.Join(dir, "invalid.json")
+	ioutil.WriteFile(invalid, []byte(`{"colors": {}}`), 0644)
+
+	navy := "navy"
+	tests := []struct {
+		path      string
+		expect    Config
+		expectErr bool
+	}{
+		{valid, Config{
+			Styles: map[string]Style{"code": {FgColor: &navy}},
+			Keys:   map[string][]string{"up": {"k"}},
+			Hosts:  map[string]Host{"git.example.com": {Type: "gitea"}},
+		}, false},
+		{filepath.Join(dir, "missing.json"), Config{}, false},
+		{"", Config{}, false},
+		{invalid, Config{}, true},
+	}
+
+	for idx, tt := range tests {
+		c, err := Load(tt.path)
+		if (err != nil) != tt.expectErr {
+			t.Errorf("[%d] Unexpected error, expected=%v, got=%v", idx, tt.expectErr, err)
+			continue
+		}
+		if !tt.expectErr && !reflect.DeepEqual(c, tt.expect) {
+			t.Errorf("[%d] Unexpected config, expected=%+v, got=%+v", idx, tt.expect, c)
+		}
+	}
+}
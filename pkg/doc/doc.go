@@ -1,7 +1,12 @@
 package doc
 
 import (
+	"context"
+	"fmt"
 	"io"
+	"path"
+	"path/filepath"
+	"strings"
 )
 
 type Style int
@@ -19,42 +24,174 @@ const (
 	Image
 	Link
 	Unknown
+
+	// Token styles are applied by syntax highlighting.
+	Keyword Style = 1000 << iota
+	Plain
+	Constant
+	String
+	Number
+	Comment
+	Operator
+	Attribute
 )
 
+// Styler applies a Style to text when a Section is rendered.
 type Styler interface {
+	// Style returns a single line of text with the Style applied.
 	Style(string, Style) string
+
+	// Indent returns the indentation of each line with the Style.
+	Indent(Style) string
 }
 
+// Resolver loads the content at a path. Resolving stops with the error of
+// the context if it is cancelled or its deadline is exceeded.
 type Resolver interface {
-	Resolve(string) (io.ReadCloser, error)
+	Resolve(context.Context, string) (io.ReadCloser, error)
 }
 
 type Parser interface {
 	Parse(io.Reader) (Document, error)
 }
 
+// Sourcer can be implemented by the content returned from a Resolver
+// to report the location the content was loaded from, when it differs
+// from the path that was resolved.
+type Sourcer interface {
+	Source() string
+}
+
 type Document struct {
+	Title   string
 	Headers []Header
+
+	// Source is the location the document was loaded from, which
+	// relative links within the document are resolved against.
+	Source string
 }
 
 type Header struct {
 	Title   string
 	Level   int
 	Content []Section
-}
 
-type Section struct {
-	Text string
+	// Anchor is the identifier used to link to the Header from
+	// within a document, such as "#installation".
+	Anchor string
+
+	// Preamble indicates that the Header does not appear in the
+	// source document, and instead holds the content that precedes
+	// the first heading.
+	Preamble bool
 }
 
-func NewDocument(path string, r Resolver, p Parser) (Document, error) {
-	content, err := r.Resolve(path)
+func NewDocument(ctx context.Context, path string, r Resolver, p Parser) (Document, error) {
+	content, err := r.Resolve(ctx, path)
 	if err != nil {
 		return Document{}, err
 	}
 	defer content.Close()
 
-	return p.Parse(content)
+	d, err := p.Parse(content)
+	if err != nil {
+		return Document{}, &ParseError{Path: path, Err: err}
+	}
+
+	d.Title = Title(path)
+	d.Source = path
+	if s, ok := content.(Sourcer); ok && s.Source() != "" {
+		d.Source = s.Source()
+	}
+
+	for i, h := range d.Headers {
+		if h.Preamble && h.Title == "" {
+			d.Headers[i].Title = d.Title
+		}
+	}
+
+	return d, nil
+}
+
+// ParseError is returned by NewDocument when the content at a path was
+// resolved, but could not be parsed.
+type ParseError struct {
+	Path string
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("unable to parse %v: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the error returned by the Parser.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// FindAnchor returns the index of the Header with the provided anchor,
+// or -1 if there is no such Header.
+func (d Document) FindAnchor(anchor string) int {
+	anchor = strings.TrimPrefix(anchor, "#")
+	for i, h := range d.Headers {
+		if h.Anchor != "" && h.Anchor == anchor {
+			return i
+		}
+	}
+	return -1
+}
+
+// FindHeader returns the index of the Header matching the name, which is
+// either its anchor or its title matched case-insensitively, or -1 if
+// there is no such Header.
+func (d Document) FindHeader(name string) int {
+	if idx := d.FindAnchor(name); idx >= 0 {
+		return idx
+	}
+
+	name = strings.TrimSpace(name)
+	for i, h := range d.Headers {
+		if name != "" && strings.EqualFold(h.Title, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Fit returns a copy of the document with each of its sections laid out
+// within the width. See Section.Fit.
+func (d Document) Fit(width int) Document {
+	if width <= 0 {
+		return d
+	}
+
+	headers := make([]Header, len(d.Headers))
+	for i, h := range d.Headers {
+		content := make([]Section, len(h.Content))
+		for j, s := range h.Content {
+			content[j] = s.Fit(width)
+		}
+
+		h.Content = content
+		headers[i] = h
+	}
+
+	d.Headers = headers
+	return d
+}
+
+// Title returns a human readable title for a document path, which is
+// the final element of the path, such as the file or repository name.
+func Title(p string) string {
+	p = strings.TrimSuffix(filepath.ToSlash(p), "/")
+	if p == "" {
+		return ""
+	} else if p == "-" {
+		// By convention, "-" refers to standard input.
+		return "stdin"
+	}
+
+	return path.Base(p)
 }
 
 // NopStyler implements a no-op Styler.
@@ -64,3 +201,8 @@ type NopStyler struct{}
 func (NopStyler) Style(s string, st Style) string {
 	return s
 }
+
+// Indent returns an empty string, applying no indentation.
+func (NopStyler) Indent(st Style) string {
+	return ""
+}
@@ -2,6 +2,7 @@ package doc
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"io/ioutil"
@@ -13,7 +14,7 @@ type mockResolver struct {
 	resolveFn func(string) (io.ReadCloser, error)
 }
 
-func (m mockResolver) Resolve(path string) (io.ReadCloser, error) {
+func (m mockResolver) Resolve(ctx context.Context, path string) (io.ReadCloser, error) {
 	return m.resolveFn(path)
 }
 
@@ -28,7 +29,14 @@ func (m mockParser) Parse(r io.Reader) (Document, error) {
 func TestNewDocument(t *testing.T) {
 	expectPath := "/path/to/file"
 	expectContent := "CONTENT"
-	var expectDoc Document
+	expectDoc := Document{
+		Title:  "file",
+		Source: "/path/to/file",
+		Headers: []Header{
+			{Title: "file", Level: 1, Preamble: true},
+			{Title: "Header", Level: 1},
+		},
+	}
 
 	r := mockResolver{
 		resolveFn: func(path string) (io.ReadCloser, error) {
@@ -51,11 +59,16 @@ func TestNewDocument(t *testing.T) {
 				t.Fatalf("Unexpected content, expected=%v, got=%s", expectContent, got)
 			}
 
-			return expectDoc, nil
+			return Document{
+				Headers: []Header{
+					{Level: 1, Preamble: true},
+					{Title: "Header", Level: 1},
+				},
+			}, nil
 		},
 	}
 
-	d, err := NewDocument(expectPath, r, p)
+	d, err := NewDocument(context.Background(), expectPath, r, p)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -75,7 +88,7 @@ func TestNewDocument_errors(t *testing.T) {
 			},
 		}
 
-		if _, err := NewDocument("", r, nil); err != expectErr {
+		if _, err := NewDocument(context.Background(), "", r, nil); err != expectErr {
 			t.Fatalf("Unexpected error, expected=%v, got=%v", expectErr, err)
 		}
 	}
@@ -94,9 +107,121 @@ func TestNewDocument_errors(t *testing.T) {
 			},
 		}
 
-		if _, err := NewDocument("", r, p); err != expectErr {
+		_, err := NewDocument(context.Background(), "README.md", r, p)
+		var pe *ParseError
+		if !errors.As(err, &pe) || pe.Err != expectErr || pe.Path != "README.md" {
 			t.Fatalf("Unexpected error, expected=%v, got=%v", expectErr, err)
 		}
+		if !errors.Is(err, expectErr) {
+			t.Errorf("Expected the ParseError to wrap the parser error, got=%v", err)
+		}
+	}
+}
+
+type mockSourcer struct {
+	io.ReadCloser
+}
+
+func (mockSourcer) Source() string {
+	return "https://example.com/README.md"
+}
+
+func TestNewDocument_source(t *testing.T) {
+	r := mockResolver{
+		resolveFn: func(path string) (io.ReadCloser, error) {
+			return mockSourcer{ioutil.NopCloser(&bytes.Buffer{})}, nil
+		},
+	}
+	p := mockParser{
+		parseFn: func(r io.Reader) (Document, error) {
+			return Document{}, nil
+		},
+	}
+
+	d, err := NewDocument(context.Background(), "example.com", r, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Source != "https://example.com/README.md" {
+		t.Errorf("Unexpected source, expected=https://example.com/README.md, got=%v", d.Source)
+	}
+}
+
+func TestDocument_FindAnchor(t *testing.T) {
+	d := Document{
+		Headers: []Header{
+			{Title: "Preamble", Preamble: true},
+			{Title: "Header 1", Anchor: "header-1"},
+			{Title: "Header 2", Anchor: "header-2"},
+		},
+	}
+
+	tests := []struct {
+		anchor string
+		expect int
+	}{
+		{"header-1", 1},
+		{"#header-2", 2},
+		{"missing", -1},
+		{"", -1},
+	}
+
+	for idx, tt := range tests {
+		if got := d.FindAnchor(tt.anchor); got != tt.expect {
+			t.Errorf("[%d] Unexpected index, expected=%v, got=%v", idx, tt.expect, got)
+		}
+	}
+}
+
+func TestDocument_FindHeader(t *testing.T) {
+	d := Document{
+		Headers: []Header{
+			{Title: "Preamble", Preamble: true},
+			{Title: "Getting Started", Anchor: "getting-started"},
+			{Title: "Usage", Anchor: "usage"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		expect int
+	}{
+		{"getting-started", 1},
+		{"#usage", 2},
+		{"Getting Started", 1},
+		{"usage", 2},
+		{" USAGE ", 2},
+		{"preamble", 0},
+		{"missing", -1},
+		{"", -1},
+	}
+
+	for idx, tt := range tests {
+		if got := d.FindHeader(tt.name); got != tt.expect {
+			t.Errorf("[%d] Unexpected index, expected=%v, got=%v", idx, tt.expect, got)
+		}
+	}
+}
+
+func TestTitle(t *testing.T) {
+	tests := []struct {
+		path   string
+		expect string
+	}{
+		{"./README.md", "README.md"},
+		{"/path/to/file.md", "file.md"},
+		{"https://example.com/docs/guide.md", "guide.md"},
+		{"github.com/KyleBanks/kurz", "kurz"},
+		{"github.com/KyleBanks/kurz/", "kurz"},
+		{"", ""},
+		{"-", "stdin"},
+	}
+
+	for idx, tt := range tests {
+		if got := Title(tt.path); got != tt.expect {
+			t.Errorf("[%d] Unexpected title, expected=%v, got=%v", idx, tt.expect, got)
+		}
 	}
 }
 
@@ -111,3 +236,20 @@ func TestNopStyler_Style(t *testing.T) {
 	}
 
 }
+
+func TestProgress(t *testing.T) {
+	// No progress function
+	Progress(context.Background(), "ignored")
+
+	var got []string
+	ctx := WithProgress(context.Background(), func(msg string) {
+		got = append(got, msg)
+	})
+	Progress(ctx, "Trying %v", "README.md")
+	Progress(ctx, "Done")
+
+	expect := []string{"Trying README.md", "Done"}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("Unexpected progress, expected=%v, got=%v", expect, got)
+	}
+}
@@ -0,0 +1,315 @@
+// Package highlight splits source code into spans styled by the type of
+// each token, allowing code blocks to be rendered with syntax highlighting.
+package highlight
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+)
+
+// Spans returns the spans of the code, styled according to the language.
+// Text that isn't part of a highlighted token, such as whitespace and
+// identifiers, is given the doc.Plain style.
+//
+// If the language is not supported, the code is returned as a single span
+// with the doc.Normal style.
+func Spans(lang, code string) []doc.Span {
+	l, ok := Lookup(lang)
+	if !ok {
+		return []doc.Span{{Text: code}}
+	}
+
+	return l.Spans(code)
+}
+
+// Supported returns true if the language can be highlighted.
+func Supported(lang string) bool {
+	_, ok := Lookup(lang)
+	return ok
+}
+
+// Lookup returns the Language with the provided name or alias, which is
+// matched case-insensitively.
+func Lookup(name string) (*Language, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, l := range Languages {
+		for _, n := range l.Names {
+			if n == name {
+				return l, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Language describes the lexical structure of a programming language.
+type Language struct {
+	// Names contains the name of the language followed by any aliases,
+	// as used in the info string of a fenced code block.
+	Names []string
+
+	Keywords  []string
+	Constants []string
+
+	// LineComments and BlockComments contain the delimiters of comments,
+	// with each BlockComment containing the opening and closing delimiter.
+	LineComments  []string
+	BlockComments [][2]string
+
+	// Quotes contains the characters that delimit strings, and
+	// MultilineQuotes contains those that may span multiple lines.
+	Quotes          string
+	MultilineQuotes string
+
+	// TripleQuotes indicates that strings may be delimited by three
+	// quote characters, such as Python's docstrings.
+	TripleQuotes bool
+
+	// Variables contains the character that prefixes variable names,
+	// such as the '$' in shell scripts.
+	Variables string
+
+	// Keys indicates that strings or words followed by a colon are
+	// highlighted as attributes, such as the keys of a JSON object.
+	Keys bool
+
+	// KeysAtLineStart restricts Keys to the first word of each line,
+	// ignoring any indentation and list markers.
+	KeysAtLineStart bool
+
+	// IdentChars contains the characters that may appear within
+	// identifiers in addition to letters, digits and underscores.
+	IdentChars string
+}
+
+// operators contains the characters highlighted as operators.
+const operators = "+-*/%=<>!&|^~?:"
+
+// Spans returns the spans of the code, styled according to the language.
+func (l *Language) Spans(code string) []doc.Span {
+	lx := lexer{lang: l, code: code, lineStart: true}
+	lx.run()
+	return lx.spans
+}
+
+// lexer splits code into styled spans.
+type lexer struct {
+	lang *Language
+	code string
+	pos  int
+
+	// lineStart is true until the first token of a line is read,
+	// ignoring indentation and list markers.
+	lineStart bool
+
+	spans []doc.Span
+}
+
+func (lx *lexer) run() {
+	for lx.pos < len(lx.code) {
+		start := lx.pos
+		style := lx.next()
+		lx.emit(lx.code[start:lx.pos], style)
+	}
+}
+
+// next reads the token at the current position, returning its Style.
+func (lx *lexer) next() doc.Style {
+	rest := lx.code[lx.pos:]
+	r, size := utf8.DecodeRuneInString(rest)
+
+	switch {
+	case r == '\n':
+		lx.pos += size
+		lx.lineStart = true
+		return doc.Plain
+
+	case unicode.IsSpace(r):
+		lx.pos += size
+		return doc.Plain
+	}
+
+	wasLineStart := lx.lineStart
+	lx.lineStart = false
+
+	if prefix, ok := hasPrefix(rest, lx.lang.LineComments); ok {
+		lx.pos += len(prefix)
+		lx.skipTo("\n", false)
+		return doc.Comment
+	}
+	for _, c := range lx.lang.BlockComments {
+		if strings.HasPrefix(rest, c[0]) {
+			lx.pos += len(c[0])
+			lx.skipTo(c[1], true)
+			return doc.Comment
+		}
+	}
+
+	switch {
+	case strings.ContainsRune(lx.lang.Quotes, r) || strings.ContainsRune(lx.lang.MultilineQuotes, r):
+		lx.readString(r)
+		if lx.isKey(wasLineStart) {
+			return doc.Attribute
+		}
+		return doc.String
+
+	case unicode.IsDigit(r):
+		lx.readWhile(func(r rune) bool {
+			return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_'
+		})
+		return doc.Number
+
+	case strings.ContainsRune(lx.lang.Variables, r):
+		lx.pos += size
+		if strings.HasPrefix(lx.code[lx.pos:], "{") {
+			lx.skipTo("}", true)
+		} else {
+			lx.readWhile(lx.isIdent)
+		}
+		return doc.Attribute
+
+	case strings.ContainsRune(operators, r):
+		lx.pos += size
+		// A list marker doesn't end the start of a line, so that the key
+		// of a YAML list item is still found.
+		if r == '-' && wasLineStart {
+			lx.lineStart = true
+		}
+		return doc.Operator
+
+	case lx.isIdent(r):
+		start := lx.pos
+		lx.readWhile(lx.isIdent)
+		word := lx.code[start:lx.pos]
+
+		switch {
+		case lx.isKey(wasLineStart):
+			return doc.Attribute
+		case contains(lx.lang.Keywords, word):
+			return doc.Keyword
+		case contains(lx.lang.Constants, word):
+			return doc.Constant
+		default:
+			return doc.Plain
+		}
+
+	default:
+		lx.pos += size
+		return doc.Plain
+	}
+}
+
+// readString reads a string opened by the quote at the current position.
+// Single line strings end at the end of the line if they are not closed.
+func (lx *lexer) readString(quote rune) {
+	q := string(quote)
+	if lx.lang.TripleQuotes && strings.HasPrefix(lx.code[lx.pos:], q+q+q) {
+		lx.pos += 3 * len(q)
+		lx.skipTo(q+q+q, true)
+		return
+	}
+
+	multiline := strings.ContainsRune(lx.lang.MultilineQuotes, quote)
+	lx.pos += len(q)
+	for lx.pos < len(lx.code) {
+		r, size := utf8.DecodeRuneInString(lx.code[lx.pos:])
+		switch {
+		case r == '\\' && quote != '`':
+			// Skip the escaped character along with the backslash.
+			if _, next := utf8.DecodeRuneInString(lx.code[lx.pos+size:]); next > 0 {
+				size += next
+			}
+		case r == quote:
+			lx.pos += size
+			return
+		case r == '\n' && !multiline:
+			return
+		}
+		lx.pos += size
+	}
+}
+
+// isKey returns true if the token that was just read is followed by a
+// colon, and should be highlighted as an attribute.
+func (lx *lexer) isKey(atLineStart bool) bool {
+	if !lx.lang.Keys || (lx.lang.KeysAtLineStart && !atLineStart) {
+		return false
+	}
+
+	rest := strings.TrimLeft(lx.code[lx.pos:], " \t")
+	if !strings.HasPrefix(rest, ":") {
+		return false
+	}
+
+	// YAML keys must be followed by whitespace, to avoid matching
+	// values such as URLs.
+	if lx.lang.KeysAtLineStart {
+		return len(rest) == 1 || rest[1] == ' ' || rest[1] == '\n'
+	}
+	return true
+}
+
+// isIdent returns true if the rune can appear within an identifier.
+func (lx *lexer) isIdent(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || strings.ContainsRune(lx.lang.IdentChars, r)
+}
+
+// readWhile advances past each rune that satisfies the function.
+func (lx *lexer) readWhile(fn func(rune) bool) {
+	for lx.pos < len(lx.code) {
+		r, size := utf8.DecodeRuneInString(lx.code[lx.pos:])
+		if !fn(r) {
+			return
+		}
+		lx.pos += size
+	}
+}
+
+// skipTo advances to the next occurrence of the delimiter, or to the end
+// of the code if there is none. If inclusive is true, the position is
+// advanced past the delimiter.
+func (lx *lexer) skipTo(delim string, inclusive bool) {
+	idx := strings.Index(lx.code[lx.pos:], delim)
+	if idx < 0 {
+		lx.pos = len(lx.code)
+		return
+	}
+
+	lx.pos += idx
+	if inclusive {
+		lx.pos += len(delim)
+	}
+}
+
+// emit appends text to the spans, merging it with the previous span if
+// they share the same Style.
+func (lx *lexer) emit(text string, style doc.Style) {
+	if n := len(lx.spans); n > 0 && lx.spans[n-1].Style == style {
+		lx.spans[n-1].Text += text
+		return
+	}
+
+	lx.spans = append(lx.spans, doc.Span{Text: text, Style: style})
+}
+
+// hasPrefix returns the first of the prefixes that the string begins with.
+func hasPrefix(s string, prefixes []string) (string, bool) {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+func contains(words []string, word string) bool {
+	for _, w := range words {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
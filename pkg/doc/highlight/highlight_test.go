@@ -0,0 +1,131 @@
+package highlight
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+)
+
+var styleNames = map[doc.Style]string{
+	doc.Normal:    "",
+	doc.Plain:     "",
+	doc.Keyword:   "kw",
+	doc.Constant:  "const",
+	doc.String:    "str",
+	doc.Number:    "num",
+	doc.Comment:   "com",
+	doc.Operator:  "op",
+	doc.Attribute: "attr",
+}
+
+// tokens renders the spans with the name of each token style, collapsing
+// the whitespace of unstyled text.
+func tokens(spans []doc.Span) string {
+	var out []string
+	for _, sp := range spans {
+		name := styleNames[sp.Style]
+		if name == "" {
+			if text := strings.Join(strings.Fields(sp.Text), " "); text != "" {
+				out = append(out, text)
+			}
+			continue
+		}
+		out = append(out, fmt.Sprintf("%v(%v)", name, sp.Text))
+	}
+	return strings.Join(out, " ")
+}
+
+func TestSpans(t *testing.T) {
+	tests := []struct {
+		lang   string
+		code   string
+		expect string
+	}{
+		{
+			"go",
+			"func main() {\n\tx := 42 // answer\n\treturn `raw\nstring`\n}",
+			"kw(func) main() { x op(:=) num(42) com(// answer) kw(return) str(`raw\nstring`) }",
+		},
+		{
+			"Golang",
+			`s := "a \"quoted\" string" /* block */ nil`,
+			`s op(:=) str("a \"quoted\" string") com(/* block */) const(nil)`,
+		},
+		{
+			"bash",
+			"# install\nif [ -z \"$HOME\" ]; then\n  go get ${PKG}\nfi",
+			`com(# install) kw(if) [ op(-) z str("$HOME") ]; kw(then) go get attr(${PKG}) kw(fi)`,
+		},
+		{
+			"json",
+			`{"name": "kurz", "stars": 10, "ok": true, "tags": null}`,
+			`{ attr("name") op(:) str("kurz") , attr("stars") op(:) num(10) , attr("ok") op(:) const(true) , attr("tags") op(:) const(null) }`,
+		},
+		{
+			"yml",
+			"# config\nname: kurz\nurl: http://example.com\nitems:\n  - key-name: yes",
+			"com(# config) attr(name) op(:) kurz attr(url) op(:) http op(://) example.com attr(items) op(:) op(-) attr(key-name) op(:) const(yes)",
+		},
+		{
+			"python",
+			"def f(self):\n    \"\"\"Doc\n    string\"\"\"\n    return None  # nothing",
+			`kw(def) f( const(self) ) op(:) str("""Doc
+    string""") kw(return) const(None) com(# nothing)`,
+		},
+		{
+			"js",
+			"const $el = `tpl ${x}`; // note",
+			"kw(const) $el op(=) str(`tpl ${x}`) ; com(// note)",
+		},
+		{
+			"unknown",
+			"func main() {}",
+			"func main() {}",
+		},
+	}
+
+	for idx, tt := range tests {
+		if got := tokens(Spans(tt.lang, tt.code)); got != tt.expect {
+			t.Errorf("[%d] Unexpected tokens, expected=\n%v\ngot=\n%v", idx, tt.expect, got)
+		}
+	}
+}
+
+func TestSpans_preservesText(t *testing.T) {
+	code := "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"Hello, 世界\")\n}\n"
+
+	for _, l := range Languages {
+		var buf strings.Builder
+		for _, sp := range l.Spans(code) {
+			buf.WriteString(sp.Text)
+		}
+		if buf.String() != code {
+			t.Errorf("[%v] Unexpected text, expected=%q, got=%q", l.Names[0], code, buf.String())
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name   string
+		expect *Language
+	}{
+		{"go", Go},
+		{" Bash ", Shell},
+		{"JSON", JSON},
+		{"yaml", YAML},
+		{"py", Python},
+		{"javascript", JavaScript},
+		{"cobol", nil},
+		{"", nil},
+	}
+
+	for idx, tt := range tests {
+		l, ok := Lookup(tt.name)
+		if l != tt.expect || ok != (tt.expect != nil) {
+			t.Errorf("[%d] Unexpected language for %q, expected=%v, got=%v", idx, tt.name, tt.expect, l)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package highlight
+
+// Languages contains the languages that can be highlighted.
+var Languages = []*Language{
+	Go,
+	Shell,
+	JSON,
+	YAML,
+	Python,
+	JavaScript,
+}
+
+var Go = &Language{
+	Names: []string{"go", "golang"},
+	Keywords: []string{
+		"break", "case", "chan", "const", "continue", "default", "defer",
+		"else", "fallthrough", "for", "func", "go", "goto", "if", "import",
+		"interface", "map", "package", "range", "return", "select", "struct",
+		"switch", "type", "var",
+	},
+	Constants:       []string{"true", "false", "nil", "iota"},
+	LineComments:    []string{"//"},
+	BlockComments:   [][2]string{{"/*", "*/"}},
+	Quotes:          `"'`,
+	MultilineQuotes: "`",
+}
+
+var Shell = &Language{
+	Names: []string{"sh", "shell", "bash", "zsh", "console"},
+	Keywords: []string{
+		"if", "then", "else", "elif", "fi", "for", "while", "until", "do",
+		"done", "case", "esac", "in", "function", "return", "export", "local",
+		"sudo",
+	},
+	Constants:       []string{"true", "false"},
+	LineComments:    []string{"#"},
+	MultilineQuotes: `"'`,
+	Variables:       "$",
+	IdentChars:      "-.",
+}
+
+var JSON = &Language{
+	Names:     []string{"json"},
+	Constants: []string{"true", "false", "null"},
+	Quotes:    `"`,
+	Keys:      true,
+}
+
+var YAML = &Language{
+	Names:           []string{"yaml", "yml"},
+	Constants:       []string{"true", "false", "null", "yes", "no", "on", "off"},
+	LineComments:    []string{"#"},
+	Quotes:          `"'`,
+	Keys:            true,
+	KeysAtLineStart: true,
+	IdentChars:      "-.",
+}
+
+var Python = &Language{
+	Names: []string{"python", "py", "python3"},
+	Keywords: []string{
+		"and", "as", "assert", "async", "await", "break", "class", "continue",
+		"def", "del", "elif", "else", "except", "finally", "for", "from",
+		"global", "if", "import", "in", "is", "lambda", "nonlocal", "not",
+		"or", "pass", "raise", "return", "try", "while", "with", "yield",
+	},
+	Constants:    []string{"True", "False", "None", "self"},
+	LineComments: []string{"#"},
+	Quotes:       `"'`,
+	TripleQuotes: true,
+}
+
+var JavaScript = &Language{
+	Names: []string{"javascript", "js", "jsx", "node", "typescript", "ts"},
+	Keywords: []string{
+		"async", "await", "break", "case", "catch", "class", "const",
+		"continue", "debugger", "default", "delete", "do", "else", "export",
+		"extends", "finally", "for", "from", "function", "if", "import", "in",
+		"instanceof", "let", "new", "of", "return", "static", "super",
+		"switch", "this", "throw", "try", "typeof", "var", "void", "while",
+		"yield",
+	},
+	Constants:       []string{"true", "false", "null", "undefined", "NaN", "Infinity"},
+	LineComments:    []string{"//"},
+	BlockComments:   [][2]string{{"/*", "*/"}},
+	Quotes:          `"'`,
+	MultilineQuotes: "`",
+	IdentChars:      "$",
+}
@@ -1,7 +1,6 @@
 package parser
 
 import (
-	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -9,18 +8,16 @@ import (
 
 	"github.com/KyleBanks/kurz/pkg/debug"
 	"github.com/KyleBanks/kurz/pkg/doc"
+	"github.com/KyleBanks/kurz/pkg/doc/highlight"
 
+	"github.com/shurcooL/sanitized_anchor_name"
 	"gopkg.in/russross/blackfriday.v2"
 )
 
-type Markdown struct {
-	Styler doc.Styler
-}
+type Markdown struct{}
 
-func NewMarkdown(s doc.Styler) Markdown {
-	return Markdown{
-		Styler: s,
-	}
+func NewMarkdown() Markdown {
+	return Markdown{}
 }
 
 func (m Markdown) Parse(r io.Reader) (doc.Document, error) {
@@ -33,6 +30,15 @@ func (m Markdown) Parse(r io.Reader) (doc.Document, error) {
 	root := md.Parse(b)
 
 	var d doc.Document
+	if preamble := m.preambleContents(root); len(preamble) > 0 {
+		d.Headers = append(d.Headers, doc.Header{
+			Level:    1,
+			Content:  preamble,
+			Preamble: true,
+		})
+	}
+
+	anchors := make(map[string]int)
 	root.Walk(func(node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
 		if !entering {
 			return blackfriday.GoToNext
@@ -40,10 +46,12 @@ func (m Markdown) Parse(r io.Reader) (doc.Document, error) {
 			return blackfriday.GoToNext
 		}
 
+		title := m.plainText(node)
 		d.Headers = append(d.Headers, doc.Header{
-			Title:   m.nodeContents(node),
+			Title:   title,
 			Level:   node.HeadingData.Level,
 			Content: m.sectionContents(node),
+			Anchor:  anchor(title, anchors),
 		})
 
 		return blackfriday.SkipChildren
@@ -52,6 +60,29 @@ func (m Markdown) Parse(r io.Reader) (doc.Document, error) {
 	return d, nil
 }
 
+// anchor returns the anchor of a heading, which is unique within the
+// document. Duplicate anchors are suffixed with a number in the same
+// manner as GitHub, such as "usage-1".
+func anchor(title string, seen map[string]int) string {
+	a := sanitized_anchor_name.Create(title)
+	n, ok := seen[a]
+	seen[a] = n + 1
+	if ok {
+		a = fmt.Sprintf("%v-%d", a, n)
+	}
+	return a
+}
+
+// preambleContents returns the sections that precede the first
+// heading of the document.
+func (m Markdown) preambleContents(root *blackfriday.Node) []doc.Section {
+	var sections []doc.Section
+	for n := root.FirstChild; n != nil && n.Type != blackfriday.Heading; n = n.Next {
+		sections = append(sections, m.newSection(n))
+	}
+	return sections
+}
+
 func (m Markdown) sectionContents(heading *blackfriday.Node) []doc.Section {
 	var sections []doc.Section
 	for n := heading.Next; n != nil && n.Type != blackfriday.Heading; n = n.Next {
@@ -60,150 +91,231 @@ func (m Markdown) sectionContents(heading *blackfriday.Node) []doc.Section {
 	return sections
 }
 
-func (m Markdown) newSection(container *blackfriday.Node) doc.Section {
-	var buf bytes.Buffer
-	var skipNext bool
-	container.Walk(func(n *blackfriday.Node, entering bool) blackfriday.WalkStatus {
-		if entering {
-			if !skipNext {
-				str := m.nodeContents(n)
-				buf.WriteString(str)
-			}
-		} else {
-			if m.appendNewline(n) {
-				buf.WriteString("\n")
-			}
-		}
-
-		if m.skipChildren(n) {
-			return blackfriday.SkipChildren
-		}
-
-		skipNext = m.skipNext(n)
-		return blackfriday.GoToNext
-	})
+func (m Markdown) newSection(n *blackfriday.Node) doc.Section {
+	s := doc.Section{
+		Kind:  sectionKind(n),
+		Spans: m.blockSpans(n, 0),
+	}
 
-	text := buf.String()
-	if !strings.HasSuffix(text, "\n") {
-		text += "\n"
+	switch n.Type {
+	case blackfriday.CodeBlock:
+		s.Lang = strings.TrimSpace(string(n.CodeBlockData.Info))
+	case blackfriday.Table:
+		s.Spans = nil
+		s.Table = m.tableContents(n)
 	}
 
-	return doc.Section{
-		Text: text,
+	return s
+}
+
+// sectionKind returns the kind of Section represented by a block Node.
+func sectionKind(n *blackfriday.Node) doc.SectionKind {
+	switch n.Type {
+	case blackfriday.BlockQuote:
+		return doc.QuoteSection
+	case blackfriday.CodeBlock:
+		return doc.CodeSection
+	case blackfriday.HorizontalRule:
+		return doc.RuleSection
+	case blackfriday.List:
+		return doc.ListSection
+	case blackfriday.Table:
+		return doc.TableSection
+	default:
+		return doc.ParagraphSection
 	}
 }
 
-func (Markdown) appendNewline(n *blackfriday.Node) bool {
+// blockSpans returns the spans of a block Node nested in depth lists.
+func (m Markdown) blockSpans(n *blackfriday.Node, depth int) []doc.Span {
+	debug.Log("Type=%v, Literal=%s\n", n.Type, n.Literal)
+
 	switch n.Type {
 
-	case blackfriday.Code:
-		fallthrough
+	case blackfriday.BlockQuote:
+		var spans []doc.Span
+		for c := n.FirstChild; c != nil; c = c.Next {
+			spans = append(spans, m.blockSpans(c, depth)...)
+		}
+		return spans
+
 	case blackfriday.CodeBlock:
-		return !strings.HasSuffix(string(n.Literal), "\n")
+		code := strings.Trim(string(n.Literal), "\n")
+		lang := strings.TrimSpace(string(n.CodeBlockData.Info))
+		return []doc.Span{
+			{Style: doc.CodeBlock, Children: highlight.Spans(lang, code)},
+			{Text: "\n"},
+		}
+
+	case blackfriday.HTMLBlock:
+		return []doc.Span{{Text: strings.TrimSpace(string(n.Literal)) + "\n"}}
+
+	case blackfriday.HorizontalRule:
+		return nil
+
+	case blackfriday.Heading:
+		return []doc.Span{
+			{Style: doc.Bold, Children: m.inlineSpans(n)},
+			{Text: "\n"},
+		}
+
+	case blackfriday.List:
+		return m.listSpans(n, depth)
 
 	case blackfriday.Paragraph:
-		return true
+		return append(m.inlineSpans(n), doc.Span{Text: "\n"})
+
+	case blackfriday.Table:
+		// Tables nested within other blocks are laid out without styling.
+		table := doc.Section{Kind: doc.TableSection, Table: m.tableContents(n)}
+		return []doc.Span{{Text: table.Text()}}
 
 	default:
-		return false
+		return []doc.Span{unknownSpan(n), {Text: "\n"}}
 	}
 }
 
-// skipChildren returns true when the children of the provided Node
-// should be skipped.
-func (Markdown) skipChildren(n *blackfriday.Node) bool {
-	switch n.Type {
-	case blackfriday.Emph:
-		fallthrough
-	case blackfriday.Strong:
-		return true
+// listSpans returns the spans of a List, prefixing each item with its bullet.
+func (m Markdown) listSpans(list *blackfriday.Node, depth int) []doc.Span {
+	var spans []doc.Span
+	indent := strings.Repeat("  ", depth)
+
+	var num int
+	for item := list.FirstChild; item != nil; item = item.Next {
+		num++
+
+		var bullet string
+		switch flags := item.ListData.ListFlags; {
+		case flags&blackfriday.ListTypeTerm != 0:
+			bullet = ""
+		case flags&blackfriday.ListTypeDefinition != 0:
+			bullet = "  "
+		case flags&blackfriday.ListTypeOrdered != 0:
+			bullet = fmt.Sprintf("%d%c ", num, item.ListData.Delimiter)
+		default:
+			bullet = fmt.Sprintf("%c ", item.ListData.BulletChar)
+		}
+
+		for c := item.FirstChild; c != nil; c = c.Next {
+			if c.Type == blackfriday.List {
+				spans = append(spans, m.listSpans(c, depth+1)...)
+				continue
+			}
+
+			spans = append(spans, doc.Span{Text: indent + bullet})
+			spans = append(spans, m.blockSpans(c, depth)...)
+
+			bullet = strings.Repeat(" ", len(bullet))
+		}
 	}
 
-	return false
+	return spans
 }
 
-// skipNext returns true when the next Node in the tree should be skipped.
-func (Markdown) skipNext(n *blackfriday.Node) bool {
-	switch n.Type {
-	// Skip the next if the direct child is a text type, as it's
-	// already been rendered and stylized. However, sometimes the child is
-	// another type, such as an image in a link, in which case we want to render it.
-	case blackfriday.Link:
-		fallthrough
-	case blackfriday.Image:
-		if n.FirstChild != nil && n.FirstChild.Type == blackfriday.Text {
-			return true
+// tableContents returns the rows and column alignments of a Table.
+func (m Markdown) tableContents(table *blackfriday.Node) *doc.Table {
+	var t doc.Table
+	table.Walk(func(n *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		if !entering || n.Type != blackfriday.TableRow {
+			return blackfriday.GoToNext
+		}
+
+		var row doc.TableRow
+		for cell := n.FirstChild; cell != nil; cell = cell.Next {
+			row.Header = cell.TableCellData.IsHeader
+			row.Cells = append(row.Cells, m.inlineSpans(cell))
+
+			if len(t.Rows) == 0 {
+				t.Align = append(t.Align, alignment(cell.TableCellData.Align))
+			}
 		}
+		t.Rows = append(t.Rows, row)
+
+		return blackfriday.SkipChildren
+	})
+	return &t
+}
+
+// alignment returns the doc.Alignment of a table cell.
+func alignment(flags blackfriday.CellAlignFlags) doc.Alignment {
+	switch flags {
+	case blackfriday.TableAlignmentCenter:
+		return doc.AlignCenter
+	case blackfriday.TableAlignmentRight:
+		return doc.AlignRight
+	default:
+		return doc.AlignLeft
 	}
+}
 
-	return false
+// inlineSpans returns the spans of the inline children of a Node.
+func (m Markdown) inlineSpans(parent *blackfriday.Node) []doc.Span {
+	var spans []doc.Span
+	for n := parent.FirstChild; n != nil; n = n.Next {
+		spans = append(spans, m.inlineSpan(n))
+	}
+	return spans
 }
 
-func (m Markdown) nodeContents(n *blackfriday.Node) string {
+// inlineSpan returns the span of an inline Node, including its children.
+func (m Markdown) inlineSpan(n *blackfriday.Node) doc.Span {
 	debug.Log("Type=%v, Literal=%s\n", n.Type, n.Literal)
 
 	switch n.Type {
 
-	case blackfriday.BlockQuote:
-		return m.Styler.Style(string(n.FirstChild.Literal), doc.BlockQuote)
-
 	case blackfriday.Code:
-		return m.Styler.Style(string(n.Literal), doc.Code)
+		return doc.Span{Text: string(n.Literal), Style: doc.Code}
 
-	case blackfriday.CodeBlock:
-		str := strings.TrimSpace(string(n.Literal))
-		return m.Styler.Style(str, doc.CodeBlock)
-
-	case blackfriday.Document:
-		return ""
+	case blackfriday.Del:
+		return doc.Span{Children: m.inlineSpans(n)}
 
 	case blackfriday.Emph:
-		return m.Styler.Style(string(n.FirstChild.Literal), doc.Italic)
+		return doc.Span{Style: doc.Italic, Children: m.inlineSpans(n)}
 
-	case blackfriday.Item:
-		return fmt.Sprintf("%s ", []byte{n.ListData.BulletChar})
+	case blackfriday.Hardbreak:
+		return doc.Span{Text: "\n"}
 
-	case blackfriday.List:
-		return ""
+	case blackfriday.HTMLSpan:
+		return doc.Span{Text: string(n.Literal)}
 
-	case blackfriday.Paragraph:
-		return string(n.Literal)
+	case blackfriday.Image:
+		return doc.Span{Style: doc.Image, Link: string(n.LinkData.Destination), Children: m.inlineSpans(n)}
+
+	case blackfriday.Link:
+		return doc.Span{Style: doc.Link, Link: string(n.LinkData.Destination), Children: m.inlineSpans(n)}
+
+	case blackfriday.Softbreak:
+		return doc.Span{Text: "\n"}
 
 	case blackfriday.Strong:
-		return m.Styler.Style(string(n.FirstChild.Literal), doc.Bold)
+		return doc.Span{Style: doc.Bold, Children: m.inlineSpans(n)}
 
 	case blackfriday.Text:
-		return string(n.Literal)
-
-		// Special nodes
+		return doc.Span{Text: string(n.Literal)}
 
-	case blackfriday.Heading:
-		// The following is required when the root element of a header is a non-text type,
-		// for instance a code block:
-		// # `code`
-		if n.FirstChild.Next != nil {
-			return m.nodeContents(n.FirstChild.Next)
-		}
-		return m.nodeContents(n.FirstChild)
+	default:
+		return unknownSpan(n)
+	}
+}
 
-	case blackfriday.Link:
-		text := m.nodeContents(n.FirstChild)
-		if len(text) > 0 {
-			text += " "
+// plainText returns the unstyled text of the inline children of a Node.
+func (m Markdown) plainText(n *blackfriday.Node) string {
+	var text func([]doc.Span) string
+	text = func(spans []doc.Span) string {
+		var str string
+		for _, sp := range spans {
+			str += sp.Text + text(sp.Children)
 		}
-		link := fmt.Sprintf("%v<%s> ", text, n.LinkData.Destination)
-		return m.Styler.Style(link, doc.Link)
+		return str
+	}
 
-	case blackfriday.Image:
-		text := m.nodeContents(n.FirstChild)
-		if len(text) > 0 {
-			text += " "
-		}
-		img := fmt.Sprintf("Image: %v<%s> ", text, n.LinkData.Destination)
-		return m.Styler.Style(img, doc.Image)
+	return strings.TrimSpace(text(m.inlineSpans(n)))
+}
 
-	default:
-		str := fmt.Sprintf("Unknown Node: {%v}", n)
-		return m.Styler.Style(str, doc.Unknown)
+// unknownSpan returns a span describing a Node that cannot be displayed.
+func unknownSpan(n *blackfriday.Node) doc.Span {
+	return doc.Span{
+		Text:  fmt.Sprintf("Unknown Node: {%v}", n),
+		Style: doc.Unknown,
 	}
 }
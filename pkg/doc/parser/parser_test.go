@@ -8,7 +8,7 @@ import (
 )
 
 func TestMarkdown_Parse_Basic(t *testing.T) {
-	m := NewMarkdown(doc.NopStyler{})
+	m := NewMarkdown()
 
 	d, err := m.Parse(bytes.NewBufferString(`
 # Header 1
@@ -28,16 +28,157 @@ And here's some ` + "`" + `code` + "`" + `.
 	assertDocsEqual(t, d, doc.Document{
 		Headers: []doc.Header{
 			{Title: "Header 1", Level: 1, Content: []doc.Section{
-				{Text: "Text goes here.\n"},
-				{Text: "Another paragraph.\n"},
+				paragraph("Text goes here.\n"),
+				paragraph("Another paragraph.\n"),
 			}},
 			{Title: "Header 2", Level: 2, Content: []doc.Section{
-				{Text: "And here's some code.\n"},
+				paragraph("And here's some code.\n"),
 			}},
 		},
 	})
 }
 
+func TestMarkdown_Parse_Preamble(t *testing.T) {
+	m := NewMarkdown()
+
+	d, err := m.Parse(bytes.NewBufferString(`
+Text before the first header.
+
+# Header 1
+
+Text goes here.
+	`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertDocsEqual(t, d, doc.Document{
+		Headers: []doc.Header{
+			{Level: 1, Preamble: true, Content: []doc.Section{
+				paragraph("Text before the first header.\n"),
+			}},
+			{Title: "Header 1", Level: 1, Content: []doc.Section{
+				paragraph("Text goes here.\n"),
+			}},
+		},
+	})
+}
+
+func TestMarkdown_Parse_NoHeaders(t *testing.T) {
+	m := NewMarkdown()
+
+	d, err := m.Parse(bytes.NewBufferString(`
+A document without any headers.
+
+Just paragraphs.
+	`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertDocsEqual(t, d, doc.Document{
+		Headers: []doc.Header{
+			{Level: 1, Preamble: true, Content: []doc.Section{
+				paragraph("A document without any headers.\n"),
+				paragraph("Just paragraphs.\n"),
+			}},
+		},
+	})
+}
+
+func TestMarkdown_Parse_Blocks(t *testing.T) {
+	m := NewMarkdown()
+
+	d, err := m.Parse(bytes.NewBufferString(`
+# Blocks
+
+Some *emphasis*, **bold** and [a link](https://example.com).
+
+- One
+- Two
+  1. Nested
+
+> Quoted text.
+
+` + "```go" + `
+func main() {}
+` + "```" + `
+
+---
+
+| A | Long B |
+|---|:---:|
+| 1 | 2 |
+	`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertDocsEqual(t, d, doc.Document{
+		Headers: []doc.Header{
+			{Title: "Blocks", Level: 1, Content: []doc.Section{
+				paragraph("Some emphasis, bold and a link <https://example.com>.\n"),
+				{Kind: doc.ListSection, Spans: []doc.Span{{Text: "- One\n- Two\n  1. Nested\n"}}},
+				{Kind: doc.QuoteSection, Spans: []doc.Span{{Text: "Quoted text.\n"}}},
+				{Kind: doc.CodeSection, Lang: "go", Spans: []doc.Span{{Text: "func main() {}\n"}}},
+				{Kind: doc.RuleSection},
+				{Kind: doc.TableSection, Spans: []doc.Span{{Text: "A │ Long B\n──┼───────\n1 │   2\n"}}},
+			}},
+		},
+	})
+
+	// Styles are carried by the spans rather than applied to the text
+	content := d.Headers[0].Content
+	expectStyles := []doc.Style{doc.Normal, doc.Italic, doc.Normal, doc.Bold, doc.Normal, doc.Link, doc.Normal, doc.Normal}
+	if len(content[0].Spans) != len(expectStyles) {
+		t.Fatalf("Unexpected span count, expected=%v, got=%v", len(expectStyles), len(content[0].Spans))
+	}
+	for i, sp := range content[0].Spans {
+		if sp.Style != expectStyles[i] {
+			t.Errorf("[%d] Unexpected style, expected=%v, got=%v", i, expectStyles[i], sp.Style)
+		}
+	}
+	if link := content[0].Spans[5].Link; link != "https://example.com" {
+		t.Errorf("Unexpected link, expected=https://example.com, got=%v", link)
+	}
+	if style := content[3].Spans[0].Style; style != doc.CodeBlock {
+		t.Errorf("Unexpected code style, expected=%v, got=%v", doc.CodeBlock, style)
+	}
+	if tokens := content[3].Spans[0].Children; len(tokens) == 0 || tokens[0].Style != doc.Keyword {
+		t.Errorf("Expected highlighted code tokens, got=%v", tokens)
+	}
+}
+
+func TestMarkdown_Parse_Anchors(t *testing.T) {
+	m := NewMarkdown()
+
+	d, err := m.Parse(bytes.NewBufferString(`
+# Getting Started
+## Usage
+## Usage
+## Usage
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []string{"getting-started", "usage", "usage-1", "usage-2"}
+	if len(d.Headers) != len(expect) {
+		t.Fatalf("Unexpected header count, expected=%v, got=%v", len(expect), len(d.Headers))
+	}
+	for i, h := range d.Headers {
+		if h.Anchor != expect[i] {
+			t.Errorf("[%d] Unexpected anchor, expected=%v, got=%v", i, expect[i], h.Anchor)
+		}
+	}
+}
+
+func paragraph(text string) doc.Section {
+	return doc.Section{
+		Spans: []doc.Span{{Text: text}},
+	}
+}
+
 func assertDocsEqual(t *testing.T, got, exp doc.Document) {
 	if len(got.Headers) != len(exp.Headers) {
 		t.Fatalf("Header count mismatch, expected=%v, got=%v", len(exp.Headers), len(got.Headers))
@@ -52,6 +193,9 @@ func assertDocsEqual(t *testing.T, got, exp doc.Document) {
 		if h1.Level != h2.Level {
 			t.Errorf("[h=%v] Unexpected level, expected=%v, got=%v", h, h2.Level, h1.Level)
 		}
+		if h1.Preamble != h2.Preamble {
+			t.Errorf("[h=%v] Unexpected preamble, expected=%v, got=%v", h, h2.Preamble, h1.Preamble)
+		}
 
 		if len(h1.Content) != len(h2.Content) {
 			t.Fatalf("[h=%v] Content length mismatch, expected=%v, got=%v", h, len(h2.Content), len(h1.Content))
@@ -61,8 +205,14 @@ func assertDocsEqual(t *testing.T, got, exp doc.Document) {
 			c1 := h1.Content[c]
 			c2 := h2.Content[c]
 
-			if c1.Text != c2.Text {
-				t.Errorf("[h=%v, c=%v] Unexpected text, expected'=%v', got'=%v'", h, c, c2.Text, c1.Text)
+			if c1.Kind != c2.Kind {
+				t.Errorf("[h=%v, c=%v] Unexpected kind, expected=%v, got=%v", h, c, c2.Kind, c1.Kind)
+			}
+			if c1.Lang != c2.Lang {
+				t.Errorf("[h=%v, c=%v] Unexpected lang, expected=%v, got=%v", h, c, c2.Lang, c1.Lang)
+			}
+			if c1.Text() != c2.Text() {
+				t.Errorf("[h=%v, c=%v] Unexpected text, expected'=%v', got'=%v'", h, c, c2.Text(), c1.Text())
 			}
 		}
 	}
@@ -0,0 +1,27 @@
+package doc
+
+import (
+	"context"
+	"fmt"
+)
+
+// progressKey is the context key of the function receiving progress.
+type progressKey struct{}
+
+// WithProgress returns a copy of the context that passes descriptions of
+// the progress made while resolving a document to the function, such as
+// the location being tried or the number of bytes downloaded.
+func WithProgress(ctx context.Context, fn func(string)) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// Progress reports progress to the function of the context, if it has one.
+// See WithProgress.
+func Progress(ctx context.Context, msg string, a ...interface{}) {
+	fn, ok := ctx.Value(progressKey{}).(func(string))
+	if !ok {
+		return
+	}
+
+	fn(fmt.Sprintf(msg, a...))
+}
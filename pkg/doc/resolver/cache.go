@@ -0,0 +1,213 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/KyleBanks/kurz/pkg/debug"
+	"github.com/KyleBanks/kurz/pkg/doc"
+)
+
+var (
+	// ErrNotCached indicates that content was requested from a Cache
+	// in offline mode, but has never been stored.
+	ErrNotCached = errors.New("content is not available offline")
+
+	// ErrNotModified indicates that a remote file has not changed since
+	// it was cached, in response to a conditional request.
+	ErrNotModified = errors.New("content has not been modified")
+)
+
+// Cache wraps a doc.Resolver, storing the content it resolves on
+// disk so that it can be served when the network is unavailable.
+type Cache struct {
+	Resolver doc.Resolver
+
+	// Dir is the directory that cached content is stored in. If this
+	// property is not set, the DefaultCacheDir will be used.
+	Dir string
+
+	// Offline forces content to be served from the cache without
+	// invoking the underlying Resolver.
+	Offline bool
+}
+
+// CacheEntry is a single document stored in a Cache.
+type CacheEntry struct {
+	Path      string    `json:"path"`
+	Source    string    `json:"source,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	FetchedAt time.Time `json:"fetchedAt"`
+
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+
+	Body []byte `json:"body"`
+}
+
+// validatorsKey is the context key of the CacheEntry that requests for
+// its Source are made conditional on.
+type validatorsKey struct{}
+
+// DefaultCacheDir returns the directory used to store cached content
+// under the user's cache directory.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "kurz"), nil
+}
+
+// Resolve loads the provided path using the underlying Resolver,
+// storing the result in the cache. Requests for the file that was cached
+// are conditional on its validators, and the cached content is returned
+// when the server reports that it has not been modified.
+//
+// If the underlying Resolver fails with any error other than ErrInvalidPath
+// or ErrAuthRequired, such as a network error or an exceeded rate limit, the
+// previously cached content is returned instead.
+// In offline mode the cache is used exclusively, and ErrNotCached is
+// returned when the path has not been cached. Content is not served from
+// the cache when the context is cancelled.
+func (c Cache) Resolve(ctx context.Context, path string) (io.ReadCloser, error) {
+	if c.Offline {
+		e, err := c.Get(path)
+		if err != nil {
+			return nil, err
+		}
+		return e.content(), nil
+	}
+
+	cached, cacheErr := c.Get(path)
+	if cacheErr == nil {
+		ctx = context.WithValue(ctx, validatorsKey{}, cached)
+	}
+
+	content, err := c.Resolver.Resolve(ctx, path)
+	if err == ErrNotModified && cacheErr == nil {
+		cached.FetchedAt = time.Now()
+		if err := c.Put(cached); err != nil {
+			debug.Log("Failed to cache %v: %v", path, err)
+		}
+		return cached.content(), nil
+	} else if errors.Is(err, ErrInvalidPath) || errors.Is(err, ErrAuthRequired) || (err != nil && ctx.Err() == context.Canceled) {
+		return nil, err
+	} else if err != nil {
+		if cacheErr != nil {
+			return nil, err
+		}
+
+		debug.Log("Serving cached copy of %v fetched at %v: %v", path, cached.FetchedAt, err)
+		return cached.content(), nil
+	}
+	defer content.Close()
+
+	e := CacheEntry{
+		Path:      path,
+		FetchedAt: time.Now(),
+	}
+	if r, ok := content.(*Response); ok {
+		e.Source = r.Location
+		e.Name = r.Name
+		e.ETag = r.ETag
+		e.LastModified = r.LastModified
+	} else if s, ok := content.(doc.Sourcer); ok {
+		e.Source = s.Source()
+	}
+
+	if e.Body, err = ioutil.ReadAll(content); err != nil {
+		return nil, err
+	}
+
+	if err := c.Put(e); err != nil {
+		debug.Log("Failed to cache %v: %v", path, err)
+	}
+
+	return e.content(), nil
+}
+
+// Get returns the cached entry for a path, or ErrNotCached if the path
+// has not been cached.
+func (c Cache) Get(path string) (CacheEntry, error) {
+	file, err := c.file(path)
+	if err != nil {
+		return CacheEntry{}, err
+	}
+
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = ErrNotCached
+		}
+		return CacheEntry{}, err
+	}
+
+	var e CacheEntry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return CacheEntry{}, err
+	}
+
+	return e, nil
+}
+
+// Put stores an entry in the cache, replacing any existing entry
+// for the same path.
+func (c Cache) Put(e CacheEntry) error {
+	file, err := c.file(e.Path)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so that a partially written
+	// entry is never read back.
+	tmp := file + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+// file returns the location of the cache entry for a path.
+func (c Cache) file(path string) (string, error) {
+	dir := c.Dir
+	if dir == "" {
+		var err error
+		if dir, err = DefaultCacheDir(); err != nil {
+			return "", err
+		}
+	}
+
+	sum := sha256.Sum256([]byte(path))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".json"), nil
+}
+
+// content returns the body of the entry as an io.ReadCloser.
+func (e CacheEntry) content() io.ReadCloser {
+	return &Response{
+		ReadCloser:   ioutil.NopCloser(bytes.NewReader(e.Body)),
+		Location:     e.Source,
+		Name:         e.Name,
+		ETag:         e.ETag,
+		LastModified: e.LastModified,
+	}
+}
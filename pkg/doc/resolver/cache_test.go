@@ -0,0 +1,194 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestCache_Resolve(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kurz-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	expectPath := "github.com/KyleBanks/kurz"
+	expectContent := "CACHED MARKDOWN"
+	networkErr := errors.New("network error")
+
+	var m mockResolver
+	c := Cache{
+		Resolver: &m,
+		Dir:      dir,
+	}
+
+	// Network error before anything has been cached
+	{
+		m.resolveFn = func(p string) (io.ReadCloser, error) {
+			return nil, networkErr
+		}
+
+		if _, err := c.Resolve(context.Background(), expectPath); err != networkErr {
+			t.Errorf("Unexpected error for uncached path, expected=%v, got=%v", networkErr, err)
+		}
+	}
+
+	// Successful resolve stores the content and validators
+	{
+		m.resolveFn = func(p string) (io.ReadCloser, error) {
+			if p != expectPath {
+				t.Errorf("Unexpected path, expected=%v, got=%v", expectPath, p)
+			}
+
+			return &Response{
+				ReadCloser: ioutil.NopCloser(bytes.NewBufferString(expectContent)),
+				Location:   "https://example.com/README.md",
+				ETag:       "ETAG",
+			}, nil
+		}
+
+		rc, err := c.Resolve(context.Background(), expectPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, _ := ioutil.ReadAll(rc)
+		if string(res) != expectContent {
+			t.Errorf("Unexpected content, expected=%v, got=%s", expectContent, res)
+		}
+
+		e, err := c.Get(expectPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Path != expectPath || e.Source != "https://example.com/README.md" || e.ETag != "ETAG" || e.FetchedAt.IsZero() {
+			t.Errorf("Unexpected cache entry, got=%+v", e)
+		}
+	}
+
+	// Unmodified content is served from the cache
+	{
+		e, err := c.Get(expectPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m.resolveFn = func(p string) (io.ReadCloser, error) {
+			return nil, ErrNotModified
+		}
+
+		rc, err := c.Resolve(context.Background(), expectPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, _ := ioutil.ReadAll(rc)
+		if string(res) != expectContent {
+			t.Errorf("Unexpected unmodified content, expected=%v, got=%s", expectContent, res)
+		}
+
+		refreshed, err := c.Get(expectPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if refreshed.ETag != "ETAG" || !refreshed.FetchedAt.After(e.FetchedAt) {
+			t.Errorf("Unexpected refreshed cache entry, got=%+v", refreshed)
+		}
+	}
+
+	// Network error serves the cached content
+	{
+		m.resolveFn = func(p string) (io.ReadCloser, error) {
+			return nil, networkErr
+		}
+
+		rc, err := c.Resolve(context.Background(), expectPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, _ := ioutil.ReadAll(rc)
+		if string(res) != expectContent {
+			t.Errorf("Unexpected cached content, expected=%v, got=%s", expectContent, res)
+		}
+	}
+
+	// Cancelled requests are never served from the cache
+	{
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		m.resolveFn = func(p string) (io.ReadCloser, error) {
+			return nil, ctx.Err()
+		}
+
+		if _, err := c.Resolve(ctx, expectPath); err != context.Canceled {
+			t.Errorf("Unexpected error for cancelled context, expected=%v, got=%v", context.Canceled, err)
+		}
+	}
+
+	// Authentication errors are never served from the cache
+	{
+		authErr := &AuthError{Host: "github.com"}
+		m.resolveFn = func(p string) (io.ReadCloser, error) {
+			return nil, authErr
+		}
+
+		if _, err := c.Resolve(context.Background(), expectPath); err != authErr {
+			t.Errorf("Unexpected error for authentication error, expected=%v, got=%v", authErr, err)
+		}
+	}
+
+	// Invalid paths are never served from the cache
+	{
+		m.resolveFn = func(p string) (io.ReadCloser, error) {
+			return nil, ErrInvalidPath
+		}
+
+		if _, err := c.Resolve(context.Background(), expectPath); err != ErrInvalidPath {
+			t.Errorf("Unexpected error for invalid path, expected=%v, got=%v", ErrInvalidPath, err)
+		}
+	}
+}
+
+func TestCache_Resolve_offline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kurz-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := Cache{
+		Resolver: &mockResolver{
+			resolveFn: func(p string) (io.ReadCloser, error) {
+				t.Fatal("Resolver should not be invoked when offline.")
+				return nil, nil
+			},
+		},
+		Dir:     dir,
+		Offline: true,
+	}
+
+	if _, err := c.Resolve(context.Background(), "https://example.com/README.md"); err != ErrNotCached {
+		t.Errorf("Unexpected error for uncached path, expected=%v, got=%v", ErrNotCached, err)
+	}
+
+	expectContent := "OFFLINE MARKDOWN"
+	if err := c.Put(CacheEntry{Path: "https://example.com/README.md", Body: []byte(expectContent)}); err != nil {
+		t.Fatal(err)
+	}
+
+	rc, err := c.Resolve(context.Background(), "https://example.com/README.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, _ := ioutil.ReadAll(rc)
+	if string(res) != expectContent {
+		t.Errorf("Unexpected content, expected=%v, got=%s", expectContent, res)
+	}
+}
@@ -0,0 +1,71 @@
+package resolver
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Credential authenticates requests to a Git host.
+type Credential struct {
+	// Username is only required by hosts such as Bitbucket.
+	Username string
+	Token    string
+}
+
+// Credentials contains the Credential of each Git host by hostname.
+type Credentials map[string]Credential
+
+// LoadCredentials reads a credentials file in the format of git's credential
+// store, such as https://TOKEN@github.com. A missing file is not an error.
+func LoadCredentials(path string) (Credentials, error) {
+	c := make(Credentials)
+	if path == "" {
+		return c, nil
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		u, err := url.Parse(text)
+		if err != nil || u.Host == "" || u.User == nil {
+			return nil, fmt.Errorf("%v:%d: expected a URL such as https://TOKEN@github.com", path, line)
+		}
+
+		cred := Credential{Token: u.User.Username()}
+		if password, ok := u.User.Password(); ok {
+			cred = Credential{Username: u.User.Username(), Token: password}
+		}
+		c[strings.ToLower(u.Hostname())] = cred
+	}
+	return c, s.Err()
+}
+
+// Lookup returns the Credential of a Git host, preferring a token from the
+// host's environment variables.
+func (c Credentials) Lookup(host string) (Credential, bool) {
+	if h, ok := gitHosts[host]; ok {
+		for _, env := range h.tokenEnv {
+			if token := os.Getenv(env); token != "" {
+				return Credential{Token: token}, true
+			}
+		}
+	}
+
+	cred, ok := c[host]
+	return cred, ok && cred.Token != ""
+}
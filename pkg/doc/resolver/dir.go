@@ -0,0 +1,85 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// markdownExtensions contains the file extensions of markdown documents.
+var markdownExtensions = []string{".md", ".markdown", ".mdown", ".mkd"}
+
+// MarkdownFiles returns the slash separated paths, relative to the root,
+// of all markdown documents within a directory and its subdirectories.
+//
+// Files and directories excluded by a .gitignore within the directory
+// are skipped, as are hidden directories such as .git.
+func MarkdownFiles(root string) ([]string, error) {
+	var files []string
+	var rules []ignoreRule
+
+	err := filepath.Walk(root, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if fi.IsDir() {
+			if rel != "." && (strings.HasPrefix(fi.Name(), ".") || ignored(rules, rel, true)) {
+				return filepath.SkipDir
+			}
+
+			base := rel
+			if base == "." {
+				base = ""
+			}
+			r, err := readGitignore(filepath.Join(p, ".gitignore"), base)
+			if err != nil {
+				return err
+			}
+			rules = append(rules, r...)
+			return nil
+		}
+
+		if isMarkdown(fi.Name()) && !ignored(rules, rel, false) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// readGitignore reads the rules of a .gitignore file, if it exists.
+func readGitignore(file, base string) ([]ignoreRule, error) {
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseGitignore(f, base)
+}
+
+// isMarkdown returns true if the file name has a markdown extension.
+func isMarkdown(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, e := range markdownExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
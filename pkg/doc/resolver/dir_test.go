@@ -0,0 +1,65 @@
+package resolver
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMarkdownFiles(t *testing.T) {
+	root, err := ioutil.TempDir("", "kurz-dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	files := map[string]string{
+		"README.md":              "",
+		"CHANGELOG.markdown":     "",
+		"main.go":                "",
+		".gitignore":             "build/\n*.tmp.md\n!keep.tmp.md\n",
+		"build/output.md":        "",
+		"docs/guide.md":          "",
+		"docs/draft.tmp.md":      "",
+		"docs/keep.tmp.md":       "",
+		"docs/api/.gitignore":    "/internal.md\n",
+		"docs/api/internal.md":   "",
+		"docs/api/public.md":     "",
+		"docs/api/sub/nested.md": "",
+		".git/description.md":    "",
+	}
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := MarkdownFiles(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []string{
+		"CHANGELOG.markdown",
+		"README.md",
+		"docs/api/public.md",
+		"docs/api/sub/nested.md",
+		"docs/guide.md",
+		"docs/keep.tmp.md",
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("Unexpected files, expected=%v, got=%v", expect, got)
+	}
+
+	// Directories can't be resolved as a file
+	if _, err := (File{}).Resolve(context.Background(), root); err != ErrDirectory {
+		t.Errorf("Unexpected error for directory, expected=%v, got=%v", ErrDirectory, err)
+	}
+}
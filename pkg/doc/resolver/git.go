@@ -1,15 +1,14 @@
 package resolver
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"net/http"
+	"path"
 	"strings"
-)
 
-const (
-	publicBitbucketTemplate = "https://bitbucket.org/%s/%s/raw/master/%s"
-	publicGithubTemplate    = "https://raw.githubusercontent.com/%s/%s/master/%s"
-	publicGitlabTemplate    = "https://gitlab.com/%s/%s/raw/master/%s"
+	"github.com/KyleBanks/kurz/pkg/doc"
 )
 
 var readmeFileNames = []string{
@@ -17,51 +16,251 @@ var readmeFileNames = []string{
 	"Readme.md", "README",
 }
 
+// defaultRefs are the branches tried, in order, when a path does not
+// specify a ref.
+var defaultRefs = []string{"master", "main"}
+
 // Git can be used to resolve a README file from its Git repository.
 //
 // Git supports the following repository types and formats:
+//
 //	Bitbucket: bitbucket.org/user/repo
 //		ex. bitbucket.org/atlassian/aui
-// 	Github: github.com/user/repo
+//	Codeberg: codeberg.org/user/repo
+//		ex. codeberg.org/forgejo/forgejo
+//	Github: github.com/user/repo
 //		ex. github.com/KyleBanks/kurz
-//  Gitlab: gitlab.com/user/repo
+//	Gitlab: gitlab.com/group/repo
 //		ex. gitlab.com/openpowerlifting/opl-data
-type Git struct{}
+//	sourcehut: git.sr.ht/~user/repo
+//		ex. git.sr.ht/~sircmpwn/scdoc
+//
+// Self-hosted servers are supported once they're added with AddHost.
+//
+// A branch, tag or commit can be provided after the repository name,
+// either with an '@' or in the format of the host's web URLs, optionally
+// followed by the path of a file or directory within the repository:
+//
+//	github.com/KyleBanks/kurz@v1.2.0
+//	github.com/KyleBanks/kurz/tree/main/docs/guide.md
+//	gitlab.com/group/subgroup/repo/-/blob/develop/docs
+//	bitbucket.org/atlassian/aui/src/master/docs
+//
+// When no ref is provided the master branch is used, falling back to main
+// if master does not exist. When the path is a directory, or is not provided,
+// the directory's README is loaded.
+//
+// Private repositories are loaded through the host's API with a token from
+// its environment variable, such as GITHUB_TOKEN, or the Credentials.
+type Git struct {
+	Credentials Credentials
+}
 
 // Resolve attempts to find and load a remote README file from a git repository.
-func (Git) Resolve(path string) (io.ReadCloser, error) {
-	components := strings.Split(path, "/")
-	if len(components) != 3 || len(components[1]) == 0 || len(components[2]) == 0 {
-		return nil, ErrInvalidPath
-	}
-
-	var template, user, repo string
-	switch strings.ToLower(components[0]) {
-	case "bitbucket.org":
-		template = publicBitbucketTemplate
-		user = components[1]
-		repo = components[2]
-	case "github.com":
-		template = publicGithubTemplate
-		user = components[1]
-		repo = components[2]
-	case "gitlab.com":
-		template = publicGitlabTemplate
-		user = components[1]
-		repo = components[2]
-	default:
-		return nil, ErrInvalidPath
+// An *AuthError is returned if the host requires a token or refuses the request.
+func (g Git) Resolve(ctx context.Context, path string) (io.ReadCloser, error) {
+	repo, err := parseRepository(path)
+	if err != nil {
+		return nil, err
 	}
 
+	cred, authenticated := g.Credentials.Lookup(repo.hostname)
+
 	var resolver URL
-	for _, r := range readmeFileNames {
-		content, err := resolver.Resolve(fmt.Sprintf(template, user, repo, r))
-		if err != nil && err != ErrInvalidPath {
-			return nil, err
-		} else if content != nil {
-			return content, nil
+	if authenticated {
+		resolver.Header = make(http.Header)
+		repo.host.forge.authorize(resolver.Header, cred)
+	}
+
+	for _, ref := range repo.refs {
+		for _, f := range repo.files() {
+			doc.Progress(ctx, "Trying %v/%v@%v", repo.project, f, ref)
+
+			u := repo.host.rawURL(repo.project, ref, f)
+			if authenticated {
+				u = repo.host.apiFile(repo.project, ref, f)
+			}
+
+			content, err := resolver.Resolve(ctx, u)
+			if err == ErrAuthRequired {
+				return nil, &AuthError{Host: repo.hostname, Rejected: authenticated}
+			} else if err == ErrForbidden {
+				return nil, &AuthError{Host: repo.hostname, Rejected: authenticated, Status: http.StatusForbidden}
+			} else if err != nil && err != ErrInvalidPath {
+				return nil, err
+			} else if content != nil {
+				r, ok := content.(*Response)
+				if ok && authenticated && isListing(r, f) {
+					r.Close()
+					continue
+				} else if ok && authenticated {
+					// Links relative to an API URL can't be resolved, so
+					// they're resolved relative to the repository instead.
+					r.Name = fmt.Sprintf("%v/%v@%v/%v", repo.hostname, repo.project, ref, f)
+				}
+				return content, nil
+			}
 		}
 	}
 
+	// Hosts report private repositories as not found without a token.
+	if !authenticated {
+		return nil, &AuthError{Host: repo.hostname, Status: http.StatusNotFound}
+	}
 	return nil, ErrInvalidPath
 }
+
+// isListing returns true if an API responded to a request for a file with
+// the listing of a directory, as GitHub and Bitbucket do.
+func isListing(r *Response, file string) bool {
+	return strings.HasPrefix(r.ContentType, "application/json") && path.Ext(file) != ".json"
+}
+
+// AuthError indicates that a Git host requires a token, rejected the token
+// provided, or refused the request with a Status of 403. A Status of 404
+// indicates a repository that wasn't found without a token.
+type AuthError struct {
+	Host     string
+	Rejected bool
+	Status   int
+}
+
+func (e *AuthError) Error() string {
+	switch {
+	case e.Status == http.StatusForbidden && e.Rejected:
+		return fmt.Sprintf("%v refused the request, check its rate limit and that the token has access to the repository", e.Host)
+	case e.Status == http.StatusForbidden:
+		return fmt.Sprintf("%v refused the request, its rate limit may have been exceeded. To raise it, %v", e.Host, e.tokenHint())
+	case e.Rejected:
+		return fmt.Sprintf("%v rejected the token provided, check that it has access to the repository", e.Host)
+	case e.Status == http.StatusNotFound:
+		return fmt.Sprintf("%v could not find the repository. If it's private, %v", e.Host, e.tokenHint())
+	}
+	return fmt.Sprintf("%v requires authentication, %v", e.Host, e.tokenHint())
+}
+
+// tokenHint describes how to provide a token for the host.
+func (e *AuthError) tokenHint() string {
+	var env []string
+	if h, ok := gitHosts[e.Host]; ok {
+		env = h.tokenEnv
+	}
+	if len(env) == 0 {
+		return "add a token for it to the credentials file"
+	}
+	return fmt.Sprintf("set %v or add a token to the credentials file", strings.Join(env, " or "))
+}
+
+// Unwrap returns ErrInvalidPath if the repository could not be found,
+// ErrForbidden if the request was refused, and ErrAuthRequired otherwise.
+func (e *AuthError) Unwrap() error {
+	switch e.Status {
+	case http.StatusNotFound:
+		return ErrInvalidPath
+	case http.StatusForbidden:
+		return ErrForbidden
+	}
+	return ErrAuthRequired
+}
+
+// repository is the location of a document within a Git repository.
+type repository struct {
+	host     gitHost
+	hostname string
+	project  string
+	refs     []string
+	path     string
+}
+
+// parseRepository parses a Git repository path into its host, project,
+// ref and path within the repository.
+func parseRepository(p string) (repository, error) {
+	components := strings.Split(strings.TrimSuffix(p, "/"), "/")
+	if len(components) < 3 {
+		return repository{}, ErrInvalidPath
+	}
+	for _, c := range components[1:] {
+		if len(c) == 0 {
+			return repository{}, ErrInvalidPath
+		}
+	}
+
+	hostname := strings.ToLower(components[0])
+	host, ok := gitHosts[hostname]
+	if !ok {
+		return repository{}, ErrInvalidPath
+	}
+
+	repo := repository{
+		host:     host,
+		hostname: hostname,
+		refs:     defaultRefs,
+	}
+
+	rest := components[1:]
+	var project []string
+	for len(rest) > 0 {
+		c := rest[0]
+
+		if len(project) >= 2 && host.isMarker(c) {
+			// GitLab separates the project from the ref with both the
+			// "-" and "tree" or "blob" markers.
+			rest = rest[1:]
+			if len(rest) > 0 && host.isMarker(rest[0]) {
+				rest = rest[1:]
+			}
+			if len(rest) == 0 {
+				return repository{}, ErrInvalidPath
+			}
+
+			repo.refs = []string{rest[0]}
+			rest = rest[1:]
+			if len(rest) > 0 && contains(host.forge.pathMarkers, rest[0]) {
+				rest = rest[1:]
+			}
+			break
+		}
+
+		if i := strings.Index(c, "@"); i >= 0 {
+			name, ref := c[:i], c[i+1:]
+			if len(name) == 0 || len(ref) == 0 {
+				return repository{}, ErrInvalidPath
+			}
+
+			project = append(project, name)
+			repo.refs = []string{ref}
+			rest = rest[1:]
+			break
+		}
+
+		if len(project) == 2 && !host.forge.nested {
+			break
+		}
+
+		project = append(project, c)
+		rest = rest[1:]
+	}
+
+	if len(project) < 2 {
+		return repository{}, ErrInvalidPath
+	}
+
+	repo.project = strings.Join(project, "/")
+	repo.path = strings.Join(rest, "/")
+	return repo, nil
+}
+
+// files returns the candidate files to load from the repository. A path
+// within the repository is tried as a file before the README files of a
+// directory, as neither can be told apart by its name.
+func (r repository) files() []string {
+	var files []string
+	if r.path != "" {
+		files = append(files, r.path)
+	}
+
+	for _, name := range readmeFileNames {
+		files = append(files, path.Join(r.path, name))
+	}
+	return files
+}
@@ -0,0 +1,124 @@
+package resolver
+
+import (
+	"bufio"
+	"io"
+	"path"
+	"strings"
+)
+
+// ignoreRule is a single pattern from a .gitignore file.
+type ignoreRule struct {
+	// base is the slash separated directory containing the .gitignore
+	// file, relative to the root being walked.
+	base string
+
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// parseGitignore reads the rules of a .gitignore file located in the
+// base directory.
+func parseGitignore(r io.Reader, base string) ([]ignoreRule, error) {
+	var rules []ignoreRule
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{base: base}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		line = strings.TrimPrefix(line, `\`)
+
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+
+		// Patterns containing a slash are relative to the .gitignore,
+		// while all others match a name at any depth.
+		if strings.Contains(line, "/") {
+			rule.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+
+		if line == "" {
+			continue
+		}
+		rule.pattern = line
+		rules = append(rules, rule)
+	}
+
+	return rules, s.Err()
+}
+
+// match returns true if the rule matches the slash separated path,
+// relative to the root being walked.
+func (r ignoreRule) match(rel string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+
+	if r.base != "" {
+		if !strings.HasPrefix(rel, r.base+"/") {
+			return false
+		}
+		rel = rel[len(r.base)+1:]
+	}
+
+	if !r.anchored {
+		return globMatch(r.pattern, path.Base(rel))
+	}
+	return globMatch(r.pattern, rel)
+}
+
+// ignored returns true if the path is excluded by the rules, with later
+// rules taking precedence over earlier ones.
+func ignored(rules []ignoreRule, rel string, isDir bool) bool {
+	var ignore bool
+	for _, r := range rules {
+		if r.match(rel, isDir) {
+			ignore = !r.negate
+		}
+	}
+	return ignore
+}
+
+// globMatch matches a slash separated path against a pattern, where each
+// segment is matched using path.Match and a "**" segment matches any
+// number of segments.
+func globMatch(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
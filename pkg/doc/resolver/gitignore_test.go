@@ -0,0 +1,52 @@
+package resolver
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIgnored(t *testing.T) {
+	rules, err := parseGitignore(strings.NewReader(`
+# Comment
+*.log
+/vendor
+build/
+docs/**/draft.md
+!important.log
+`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub, err := parseGitignore(strings.NewReader("local.md\n"), "sub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules = append(rules, sub...)
+
+	tests := []struct {
+		path   string
+		isDir  bool
+		expect bool
+	}{
+		{"debug.log", false, true},
+		{"nested/debug.log", false, true},
+		{"important.log", false, false},
+		{"vendor", true, true},
+		{"nested/vendor", true, false},
+		{"build", true, true},
+		{"build", false, false},
+		{"docs/draft.md", false, true},
+		{"docs/a/b/draft.md", false, true},
+		{"draft.md", false, false},
+		{"sub/local.md", false, true},
+		{"local.md", false, false},
+		{"README.md", false, false},
+	}
+
+	for idx, tt := range tests {
+		if got := ignored(rules, tt.path, tt.isDir); got != tt.expect {
+			t.Errorf("[%d] Unexpected result for %v, expected=%v, got=%v", idx, tt.path, tt.expect, got)
+		}
+	}
+}
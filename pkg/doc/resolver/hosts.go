@@ -0,0 +1,304 @@
+package resolver
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Host configures a self-hosted Git server. See AddHost.
+type Host struct {
+	// Type is the software the server runs, such as gitlab or gitea.
+	Type string
+
+	// URL defaults to https:// followed by the hostname.
+	URL string
+
+	// Template is the URL of raw files, with {project}, {ref}, {path}
+	// and optionally {url} placeholders.
+	Template string
+
+	// API is the base URL of the API used when a token is available.
+	API string
+}
+
+// forge describes the repositories of a type of Git hosting software.
+type forge struct {
+	// template is the default URL of raw files.
+	template string
+
+	// apiPath is the default path of the API, relative to the URL.
+	apiPath string
+
+	// api returns the URL of a file served by the API.
+	api func(base, project, ref, file string) string
+
+	// authorize adds a Credential to the headers of a request to the api.
+	authorize func(h http.Header, c Credential)
+
+	// nested indicates that projects may be nested in groups.
+	nested bool
+
+	// markers precede a ref in web URLs, such as "tree".
+	markers []string
+
+	// pathMarkers may follow a ref in web URLs, preceding a path.
+	pathMarkers []string
+}
+
+var (
+	githubForge = forge{
+		template:  "{url}/{project}/raw/{ref}/{path}",
+		apiPath:   "/api/v3",
+		api:       githubAPI,
+		authorize: githubAuthorize,
+		markers:   []string{"tree", "blob"},
+	}
+	gitlabForge = forge{
+		template:  "{url}/{project}/raw/{ref}/{path}",
+		apiPath:   "/api/v4",
+		api:       gitlabAPI,
+		authorize: gitlabAuthorize,
+		nested:    true,
+		markers:   []string{"-", "tree", "blob"},
+	}
+	bitbucketForge = forge{
+		template:  "{url}/{project}/raw/{ref}/{path}",
+		api:       bitbucketAPI,
+		authorize: bitbucketAuthorize,
+		markers:   []string{"src"},
+	}
+	giteaForge = forge{
+		template:  "{url}/{project}/raw/{ref}/{path}",
+		apiPath:   "/api/v1",
+		api:       giteaAPI,
+		authorize: tokenAuthorize,
+		markers:   []string{"src", "branch", "tag", "commit"},
+	}
+	gogsForge = forge{
+		template:  "{url}/{project}/raw/{ref}/{path}",
+		apiPath:   "/api/v1",
+		api:       gogsAPI,
+		authorize: tokenAuthorize,
+		markers:   []string{"src"},
+	}
+	sourcehutForge = forge{
+		template:    "{url}/{project}/blob/{ref}/{path}",
+		apiPath:     "/api",
+		api:         sourcehutAPI,
+		authorize:   bearerAuthorize,
+		markers:     []string{"tree", "blob"},
+		pathMarkers: []string{"item"},
+	}
+)
+
+// forges contains the types of servers supported by AddHost.
+var forges = map[string]forge{
+	"forgejo":   giteaForge,
+	"gitea":     giteaForge,
+	"github":    githubForge,
+	"gitlab":    gitlabForge,
+	"gogs":      gogsForge,
+	"sourcehut": sourcehutForge,
+}
+
+// gitHost is a server that Git repositories are loaded from.
+type gitHost struct {
+	forge    forge
+	url      string
+	template string
+	apiURL   string
+
+	// tokenEnv contains the environment variables providing a token.
+	tokenEnv []string
+}
+
+var gitHosts = map[string]gitHost{
+	"bitbucket.org": {
+		forge:    bitbucketForge,
+		url:      "https://bitbucket.org",
+		template: bitbucketForge.template,
+		apiURL:   "https://api.bitbucket.org/2.0",
+		tokenEnv: []string{"BITBUCKET_TOKEN"},
+	},
+	"codeberg.org": {
+		forge:    giteaForge,
+		url:      "https://codeberg.org",
+		template: giteaForge.template,
+		apiURL:   "https://codeberg.org/api/v1",
+		tokenEnv: []string{"CODEBERG_TOKEN"},
+	},
+	"git.sr.ht": {
+		forge:    sourcehutForge,
+		url:      "https://git.sr.ht",
+		template: sourcehutForge.template,
+		apiURL:   "https://git.sr.ht/api",
+		tokenEnv: []string{"SRHT_TOKEN"},
+	},
+	"github.com": {
+		forge:    githubForge,
+		url:      "https://github.com",
+		template: "https://raw.githubusercontent.com/{project}/{ref}/{path}",
+		apiURL:   "https://api.github.com",
+		tokenEnv: []string{"GITHUB_TOKEN", "GH_TOKEN"},
+	},
+	"gitlab.com": {
+		forge:    gitlabForge,
+		url:      "https://gitlab.com",
+		template: gitlabForge.template,
+		apiURL:   "https://gitlab.com/api/v4",
+		tokenEnv: []string{"GITLAB_TOKEN"},
+	},
+}
+
+// AddHost allows repositories to be loaded from a self-hosted Git server,
+// using paths that begin with its hostname.
+func AddHost(hostname string, h Host) error {
+	hostname = strings.ToLower(hostname)
+	if hostname == "" || strings.Contains(hostname, "/") {
+		return fmt.Errorf("invalid hostname '%v'", hostname)
+	}
+
+	f, ok := forges[h.Type]
+	if !ok {
+		return fmt.Errorf("unknown type '%v' for host %v, expected one of %v", h.Type, hostname, forgeNames())
+	}
+
+	if h.URL == "" {
+		h.URL = "https://" + hostname
+	}
+	if u, err := url.Parse(h.URL); err != nil || !isHTTP(u) || u.Host == "" {
+		return fmt.Errorf("invalid URL '%v' for host %v", h.URL, hostname)
+	}
+	h.URL = strings.TrimSuffix(h.URL, "/")
+
+	if h.Template == "" {
+		h.Template = f.template
+	}
+	for _, p := range []string{"{project}", "{ref}", "{path}"} {
+		if !strings.Contains(h.Template, p) {
+			return fmt.Errorf("invalid template '%v' for host %v, missing %v", h.Template, hostname, p)
+		}
+	}
+
+	if h.API == "" {
+		h.API = h.URL + f.apiPath
+	}
+
+	gitHosts[hostname] = gitHost{
+		forge:    f,
+		url:      h.URL,
+		template: h.Template,
+		apiURL:   strings.TrimSuffix(h.API, "/"),
+	}
+	return nil
+}
+
+// forgeNames returns the names of the forges.
+func forgeNames() string {
+	var names []string
+	for n := range forges {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// rawURL returns the URL of a raw file in a repository on the host.
+func (h gitHost) rawURL(project, ref, file string) string {
+	return strings.NewReplacer(
+		"{url}", h.url,
+		"{project}", project,
+		"{ref}", ref,
+		"{path}", file,
+	).Replace(h.template)
+}
+
+// apiFile returns the URL of a file served by the host's API.
+func (h gitHost) apiFile(project, ref, file string) string {
+	return h.forge.api(h.apiURL, project, ref, file)
+}
+
+// isMarker returns true if the path component precedes a ref.
+func (h gitHost) isMarker(c string) bool {
+	return contains(h.forge.markers, c)
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func githubAPI(base, project, ref, file string) string {
+	return fmt.Sprintf("%v/repos/%v/contents/%v?ref=%v", base, project, escapePath(file), url.QueryEscape(ref))
+}
+
+func gitlabAPI(base, project, ref, file string) string {
+	return fmt.Sprintf("%v/projects/%v/repository/files/%v/raw?ref=%v", base, url.PathEscape(project), url.PathEscape(file), url.QueryEscape(ref))
+}
+
+func bitbucketAPI(base, project, ref, file string) string {
+	return fmt.Sprintf("%v/repositories/%v/src/%v/%v", base, project, url.PathEscape(ref), escapePath(file))
+}
+
+func giteaAPI(base, project, ref, file string) string {
+	return fmt.Sprintf("%v/repos/%v/raw/%v?ref=%v", base, project, escapePath(file), url.QueryEscape(ref))
+}
+
+func gogsAPI(base, project, ref, file string) string {
+	return fmt.Sprintf("%v/repos/%v/raw/%v/%v", base, project, url.PathEscape(ref), escapePath(file))
+}
+
+// sourcehutAPI separates the owner and name of the project in the URL.
+func sourcehutAPI(base, project, ref, file string) string {
+	owner, name := project, ""
+	if i := strings.LastIndex(project, "/"); i >= 0 {
+		owner, name = project[:i], project[i+1:]
+	}
+	return fmt.Sprintf("%v/%v/repos/%v/blob/%v/%v", base, owner, name, url.PathEscape(ref), escapePath(file))
+}
+
+// githubAuthorize also requests the raw content of the file.
+func githubAuthorize(h http.Header, c Credential) {
+	h.Set("Authorization", "Bearer "+c.Token)
+	h.Set("Accept", "application/vnd.github.raw")
+}
+
+func bearerAuthorize(h http.Header, c Credential) {
+	h.Set("Authorization", "Bearer "+c.Token)
+}
+
+func gitlabAuthorize(h http.Header, c Credential) {
+	h.Set("PRIVATE-TOKEN", c.Token)
+}
+
+func tokenAuthorize(h http.Header, c Credential) {
+	h.Set("Authorization", "token "+c.Token)
+}
+
+// bitbucketAuthorize uses basic authentication for app passwords.
+func bitbucketAuthorize(h http.Header, c Credential) {
+	if c.Username == "" {
+		h.Set("Authorization", "Bearer "+c.Token)
+		return
+	}
+
+	auth := base64.StdEncoding.EncodeToString([]byte(c.Username + ":" + c.Token))
+	h.Set("Authorization", "Basic "+auth)
+}
+
+// escapePath escapes each component of a slash-separated path.
+func escapePath(p string) string {
+	components := strings.Split(p, "/")
+	for i, c := range components {
+		components[i] = url.PathEscape(c)
+	}
+	return strings.Join(components, "/")
+}
@@ -0,0 +1,155 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAddHost(t *testing.T) {
+	defer delete(gitHosts, "git.example.com")
+
+	tests := []struct {
+		host         Host
+		expectErr    bool
+		expectRaw    string
+		expectAPIURL string
+	}{
+		{Host{Type: "gitlab"}, false, "https://git.example.com/team/service/raw/main/README.md", "https://git.example.com/api/v4"},
+		{Host{Type: "github", URL: "https://example.com/github/"}, false, "https://example.com/github/team/service/raw/main/README.md", "https://example.com/github/api/v3"},
+		{Host{Type: "gitea", API: "https://api.example.com/"}, false, "https://git.example.com/team/service/raw/main/README.md", "https://api.example.com"},
+		{Host{Type: "gogs", Template: "{url}/{project}/raw/branch/{ref}/{path}"}, false, "https://git.example.com/team/service/raw/branch/main/README.md", "https://git.example.com/api/v1"},
+		{Host{Type: "bitbucket"}, true, "", ""},
+		{Host{Type: "missing"}, true, "", ""},
+		{Host{Type: "gitea", URL: "git.example.com"}, true, "", ""},
+		{Host{Type: "gitea", Template: "{url}/{project}/raw/{path}"}, true, "", ""},
+	}
+
+	for idx, tt := range tests {
+		delete(gitHosts, "git.example.com")
+
+		err := AddHost("Git.Example.com", tt.host)
+		if (err != nil) != tt.expectErr {
+			t.Errorf("[%d] Unexpected error, expectErr=%v, got=%v", idx, tt.expectErr, err)
+			continue
+		}
+
+		h, ok := gitHosts["git.example.com"]
+		if ok == tt.expectErr {
+			t.Errorf("[%d] Unexpected host registration, expected=%v, got=%v", idx, !tt.expectErr, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		if raw := h.rawURL("team/service", "main", "README.md"); raw != tt.expectRaw {
+			t.Errorf("[%d] Unexpected raw URL, expected=%v, got=%v", idx, tt.expectRaw, raw)
+		}
+		if h.apiURL != tt.expectAPIURL {
+			t.Errorf("[%d] Unexpected API URL, expected=%v, got=%v", idx, tt.expectAPIURL, h.apiURL)
+		}
+	}
+
+	if err := AddHost("", Host{Type: "gitea"}); err == nil {
+		t.Error("Expected an error for an empty hostname")
+	}
+}
+
+func TestGit_Resolve_selfHosted(t *testing.T) {
+	unsetTokens(t)
+	oldDefaultHttp := DefaultHttpGetter
+	defer func() {
+		DefaultHttpGetter = oldDefaultHttp
+	}()
+	DefaultHttpGetter = http.DefaultClient
+
+	// The server stands in for each forge, serving a single file at the
+	// raw and API paths, which require the token.
+	expectContent := "SELF HOSTED MARKDOWN"
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.RequestURI())
+
+		switch r.URL.RequestURI() {
+		case "/team/service/raw/main/docs/guide.md",
+			"/~team/service/blob/v1/README.md",
+			"/team/service/raw/master/README.md":
+		case "/api/v4/projects/team%2Fservice/repository/files/README.md/raw?ref=master":
+			if r.Header.Get("PRIVATE-TOKEN") != "TOKEN" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		case "/api/v1/repos/team/service/raw/README.md?ref=master":
+			if r.Header.Get("Authorization") != "token TOKEN" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(expectContent))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		host      string
+		forge     string
+		path      string
+		cred      Credentials
+		expectErr error
+	}{
+		{"github.corp.example.com", "github", "github.corp.example.com/team/service/blob/main/docs/guide.md", nil, nil},
+		{"gitea.corp.example.com", "gitea", "gitea.corp.example.com/team/service/src/branch/main/docs/guide.md", nil, nil},
+		{"git.corp.example.com", "forgejo", "git.corp.example.com/team/service", nil, nil},
+		{"git.corp.example.com", "gitea", "git.corp.example.com/team/service", Credentials{"git.corp.example.com": {Token: "TOKEN"}}, nil},
+		{"git.corp.example.com", "gitea", "git.corp.example.com/team/service", Credentials{"git.corp.example.com": {Token: "WRONG"}}, ErrAuthRequired},
+		{"gitlab.corp.example.com", "gitlab", "gitlab.corp.example.com/team/service", Credentials{"gitlab.corp.example.com": {Token: "TOKEN"}}, nil},
+		{"gogs.corp.example.com", "gogs", "gogs.corp.example.com/team/service/src/main/docs/guide.md", nil, nil},
+		{"sr.corp.example.com", "sourcehut", "sr.corp.example.com/~team/service/tree/v1/item/README.md", nil, nil},
+		{"missing.corp.example.com", "gitea", "missing.corp.example.com/team/missing", nil, ErrInvalidPath},
+	}
+
+	for idx, tt := range tests {
+		requested = nil
+		if err := AddHost(tt.host, Host{Type: tt.forge, URL: srv.URL}); err != nil {
+			t.Fatal(err)
+		}
+
+		g := Git{Credentials: tt.cred}
+		res, err := g.Resolve(context.Background(), tt.path)
+		delete(gitHosts, tt.host)
+
+		if tt.expectErr != nil {
+			if !errors.Is(err, tt.expectErr) {
+				t.Errorf("[%d] Unexpected error, expected=%v, got=%v", idx, tt.expectErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] Unexpected error, expected=nil, got=%v (requested %v)", idx, err, requested)
+			continue
+		}
+
+		content, _ := ioutil.ReadAll(res)
+		if string(content) != expectContent {
+			t.Errorf("[%d] Unexpected content, expected=%v, got=%s", idx, expectContent, content)
+		}
+	}
+}
+
+func TestJoin_selfHosted(t *testing.T) {
+	defer delete(gitHosts, "git.corp.example.com")
+	if err := AddHost("git.corp.example.com", Host{Type: "gitlab"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := "git.corp.example.com/team/service"
+	if got := Join("README.md", "https://git.corp.example.com/team/service/"); got != expect {
+		t.Errorf("Unexpected link, expected=%v, got=%v", expect, got)
+	}
+}
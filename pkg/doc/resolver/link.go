@@ -0,0 +1,41 @@
+package resolver
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// Join resolves a link found within a document against the source the
+// document was loaded from, returning a path that can be resolved.
+//
+// Relative links are resolved against the URL or directory of the source,
+// while absolute links are returned as-is, with the exception of links to
+// supported Git hosts which are converted to a path the Git resolver accepts.
+func Join(source, link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+
+	if u.Scheme != "" {
+		if _, ok := gitHosts[strings.ToLower(u.Host)]; ok && isHTTP(u) {
+			return u.Host + strings.TrimSuffix(u.Path, "/")
+		}
+		return link
+	}
+
+	if base, err := url.Parse(source); err == nil && isHTTP(base) {
+		return base.ResolveReference(u).String()
+	}
+
+	if filepath.IsAbs(link) {
+		return link
+	}
+	return filepath.Join(filepath.Dir(source), filepath.FromSlash(link))
+}
+
+// isHTTP returns true if the URL uses the http or https scheme.
+func isHTTP(u *url.URL) bool {
+	return u.Scheme == "http" || u.Scheme == "https"
+}
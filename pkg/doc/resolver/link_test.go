@@ -0,0 +1,33 @@
+package resolver
+
+import "testing"
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		source string
+		link   string
+		expect string
+	}{
+		// Local files
+		{"README.md", "docs/guide.md", "docs/guide.md"},
+		{"./docs/README.md", "guide.md", "docs/guide.md"},
+		{"/path/to/docs/README.md", "../CHANGELOG.md", "/path/to/CHANGELOG.md"},
+		{"/path/to/README.md", "/etc/guide.md", "/etc/guide.md"},
+
+		// Remote files
+		{"https://example.com/docs/README.md", "guide.md", "https://example.com/docs/guide.md"},
+		{"https://example.com/docs/README.md", "/guide.md", "https://example.com/guide.md"},
+		{"https://raw.githubusercontent.com/KyleBanks/kurz/master/README.md", "docs/guide.md", "https://raw.githubusercontent.com/KyleBanks/kurz/master/docs/guide.md"},
+
+		// Absolute links
+		{"README.md", "https://example.com/guide.md", "https://example.com/guide.md"},
+		{"README.md", "https://github.com/KyleBanks/kurz/blob/master/README.md", "github.com/KyleBanks/kurz/blob/master/README.md"},
+		{"README.md", "https://gitlab.com/group/repo/", "gitlab.com/group/repo"},
+	}
+
+	for idx, tt := range tests {
+		if got := Join(tt.source, tt.link); got != tt.expect {
+			t.Errorf("[%d] Unexpected path, expected=%v, got=%v", idx, tt.expect, got)
+		}
+	}
+}
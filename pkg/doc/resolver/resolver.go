@@ -1,11 +1,14 @@
 package resolver
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/KyleBanks/kurz/pkg/doc"
 )
@@ -17,17 +20,46 @@ var (
 	// For example, if you pass a remote URL to a resolver.File,
 	// you'll receive this error.
 	ErrInvalidPath = errors.New("cannot resolve the path provided")
+
+	// ErrDirectory indicates that a local path is a directory rather
+	// than a file. See MarkdownFiles to find the documents within it.
+	ErrDirectory = errors.New("path is a directory")
+
+	// ErrAuthRequired indicates that a server requires authentication to
+	// access a file, or rejected the credentials provided.
+	ErrAuthRequired = errors.New("authentication required")
+
+	// ErrForbidden indicates that a server refused to provide a file, such
+	// as when a rate limit has been exceeded.
+	ErrForbidden = errors.New("access forbidden")
 )
 
 // HttpGetter defines a type that can send GET requests
 // over HTTP.
 type HttpGetter interface {
-	Get(string) (*http.Response, error)
+	Do(*http.Request) (*http.Response, error)
 }
 
 // DefaultHttpGetter is the default HTTP implementation
 // used when performing HTTP requests.
-var DefaultHttpGetter HttpGetter = http.DefaultClient
+var DefaultHttpGetter HttpGetter = &http.Client{CheckRedirect: checkRedirect}
+
+// authHeaders are the headers that credentials are sent in.
+var authHeaders = []string{"Authorization", "PRIVATE-TOKEN"}
+
+// checkRedirect follows up to 10 redirects like the http.DefaultClient,
+// removing credentials from requests that are redirected to another host.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	if req.URL.Host != via[0].URL.Host {
+		for _, h := range authHeaders {
+			req.Header.Del(h)
+		}
+	}
+	return nil
+}
 
 // Chain runs a sequence of doc.Resolver types, returning the first
 // content to be successfully resolved.
@@ -41,9 +73,13 @@ type Chain struct {
 // If any of the resolvers returns an error other than ErrInvalidPath,
 // the error will be returned. If none of the resolvers are able to
 // resolve a content body, an ErrInvalidPath error is returned.
-func (c Chain) Resolve(path string) (io.ReadCloser, error) {
+func (c Chain) Resolve(ctx context.Context, path string) (io.ReadCloser, error) {
 	for _, r := range c.Resolvers {
-		content, err := r.Resolve(path)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		content, err := r.Resolve(ctx, path)
 		if err != nil && err != ErrInvalidPath {
 			return nil, err
 		} else if content != nil {
@@ -58,7 +94,7 @@ func (c Chain) Resolve(path string) (io.ReadCloser, error) {
 type File struct{}
 
 // Resolve finds and loads a local file by its path.
-func (File) Resolve(path string) (io.ReadCloser, error) {
+func (File) Resolve(ctx context.Context, path string) (io.ReadCloser, error) {
 	path, err := filepath.Abs(path)
 	if err != nil {
 		return nil, err
@@ -72,32 +108,135 @@ func (File) Resolve(path string) (io.ReadCloser, error) {
 		return nil, err
 	}
 
+	if fi, err := f.Stat(); err == nil && fi.IsDir() {
+		f.Close()
+		return nil, ErrDirectory
+	}
+
 	return f, nil
 }
 
+// Response is the content body of a remote file along with its
+// location, content type and the cache validators returned by the server.
+type Response struct {
+	io.ReadCloser
+
+	Location     string
+	ContentType  string
+	ETag         string
+	LastModified string
+
+	// Name is the location the content is displayed as and its links are
+	// resolved against, when it differs from the URL it was fetched from.
+	Name string
+}
+
+// Source returns the Name of the content, or the URL it was loaded
+// from if it has no Name.
+func (r *Response) Source() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.Location
+}
+
 // URL can be used to resolve a remote file by its URL.
 type URL struct {
 	// HttpGetter allows for a custom HTTP client implementation
 	// to be used by the URL resolver. If this property is not
 	// set, the DefaultHttpGetter will be used.
 	HttpGetter HttpGetter
+
+	// Header contains additional headers to send, such as authentication.
+	Header http.Header
 }
 
 // Resolve finds and loads a remote file by its URL.
-func (u URL) Resolve(url string) (io.ReadCloser, error) {
+//
+// Only http and https URLs are supported, any other path results
+// in an ErrInvalidPath error. Responses of 401 and 403 result in
+// ErrAuthRequired and ErrForbidden. The number of bytes read is reported
+// to the context's progress function.
+//
+// Requests for a file stored in a Cache are sent with its validators, and
+// ErrNotModified is returned if the server reports the file is unchanged.
+func (u URL) Resolve(ctx context.Context, url string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, ErrInvalidPath
+	}
+
 	var h HttpGetter = u.HttpGetter
 	if h == nil {
 		h = DefaultHttpGetter
 	}
 
-	resp, err := h.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range u.Header {
+		req.Header[k] = v
+	}
+	if e, ok := ctx.Value(validatorsKey{}).(CacheEntry); ok && e.Source == url {
+		if e.ETag != "" {
+			req.Header.Set("If-None-Match", e.ETag)
+		}
+		if e.LastModified != "" {
+			req.Header.Set("If-Modified-Since", e.LastModified)
+		}
+	}
+
+	resp, err := h.Do(req)
 	if err != nil {
 		return nil, err
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if resp.Body != nil {
+			resp.Body.Close()
+		}
+		switch resp.StatusCode {
+		case http.StatusNotModified:
+			return nil, ErrNotModified
+		case http.StatusUnauthorized:
+			return nil, ErrAuthRequired
+		case http.StatusForbidden:
+			return nil, ErrForbidden
+		}
 		return nil, ErrInvalidPath
 	}
 
-	return resp.Body, nil
+	return &Response{
+		ReadCloser:   &progressReader{ReadCloser: resp.Body, ctx: ctx, url: url},
+		Location:     url,
+		ContentType:  resp.Header.Get("Content-Type"),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}, nil
+}
+
+// progressReader reports the number of bytes read from a content body.
+type progressReader struct {
+	io.ReadCloser
+
+	ctx  context.Context
+	url  string
+	read int
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.ReadCloser.Read(b)
+	if n > 0 {
+		p.read += n
+		doc.Progress(p.ctx, "Downloading %v (%v)", p.url, byteSize(p.read))
+	}
+	return n, err
+}
+
+// byteSize formats a number of bytes for display.
+func byteSize(n int) string {
+	if n < 1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+	return fmt.Sprintf("%.1f KB", float64(n)/1024)
 }
@@ -2,10 +2,13 @@ package resolver
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/KyleBanks/kurz/pkg/doc"
@@ -15,16 +18,20 @@ type mockResolver struct {
 	resolveFn func(string) (io.ReadCloser, error)
 }
 
-func (m *mockResolver) Resolve(p string) (io.ReadCloser, error) {
+func (m *mockResolver) Resolve(ctx context.Context, p string) (io.ReadCloser, error) {
 	return m.resolveFn(p)
 }
 
 type mockHttpGetter struct {
 	getFn func(string) (*http.Response, error)
+	doFn  func(*http.Request) (*http.Response, error)
 }
 
-func (m *mockHttpGetter) Get(url string) (*http.Response, error) {
-	return m.getFn(url)
+func (m *mockHttpGetter) Do(req *http.Request) (*http.Response, error) {
+	if m.doFn != nil {
+		return m.doFn(req)
+	}
+	return m.getFn(req.URL.String())
 }
 
 func TestChain_Resolve(t *testing.T) {
@@ -59,7 +66,7 @@ func TestChain_Resolve(t *testing.T) {
 		},
 	}
 
-	rc, err := c.Resolve(expectPath)
+	rc, err := c.Resolve(context.Background(), expectPath)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -99,7 +106,7 @@ func TestChain_Resolve_wholeChain(t *testing.T) {
 		},
 	}
 
-	_, err := c.Resolve("path")
+	_, err := c.Resolve(context.Background(), "path")
 	if err != expectErr {
 		t.Errorf("Unexpected error, expected=%v, got=%v", expectErr, err)
 	}
@@ -115,6 +122,26 @@ func TestChain_Resolve_wholeChain(t *testing.T) {
 	}
 }
 
+func TestChain_Resolve_cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := Chain{
+		Resolvers: []doc.Resolver{
+			&mockResolver{
+				resolveFn: func(p string) (io.ReadCloser, error) {
+					t.Fatal("Resolver should not have been invoked.")
+					return nil, nil
+				},
+			},
+		},
+	}
+
+	if _, err := c.Resolve(ctx, "path"); err != context.Canceled {
+		t.Errorf("Unexpected error, expected=%v, got=%v", context.Canceled, err)
+	}
+}
+
 func TestURL_Resolve(t *testing.T) {
 	var m mockHttpGetter
 	u := URL{
@@ -136,7 +163,7 @@ func TestURL_Resolve(t *testing.T) {
 			}, nil
 		}
 
-		rc, err := u.Resolve(expectUrl)
+		rc, err := u.Resolve(context.Background(), expectUrl)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -147,6 +174,31 @@ func TestURL_Resolve(t *testing.T) {
 		}
 	}
 
+	// Progress
+	{
+		m.getFn = func(url string) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(strings.Repeat("a", 2048))),
+			}, nil
+		}
+
+		var progress []string
+		ctx := doc.WithProgress(context.Background(), func(msg string) {
+			progress = append(progress, msg)
+		})
+		rc, err := u.Resolve(ctx, "http://example.com/FILE.md")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ioutil.ReadAll(rc)
+
+		expect := "Downloading http://example.com/FILE.md (2.0 KB)"
+		if len(progress) == 0 || progress[len(progress)-1] != expect {
+			t.Errorf("Unexpected progress, expected=%v, got=%v", expect, progress)
+		}
+	}
+
 	// Bad status code
 	{
 		expectErr := ErrInvalidPath
@@ -156,12 +208,74 @@ func TestURL_Resolve(t *testing.T) {
 			}, nil
 		}
 
-		_, err := u.Resolve("URL")
+		_, err := u.Resolve(context.Background(), "http://example.com/FILE.md")
 		if err != expectErr {
 			t.Errorf("Unexpected error for bad status code, expected=%v, got=%v", expectErr, err)
 		}
 	}
 
+	// Authentication required or forbidden
+	for status, expectErr := range map[int]error{401: ErrAuthRequired, 403: ErrForbidden} {
+		m.getFn = func(url string) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: status,
+			}, nil
+		}
+
+		_, err := u.Resolve(context.Background(), "http://example.com/FILE.md")
+		if err != expectErr {
+			t.Errorf("Unexpected error for status %v, expected=%v, got=%v", status, expectErr, err)
+		}
+	}
+
+	// Headers
+	{
+		u := URL{
+			HttpGetter: &mockHttpGetter{
+				doFn: func(req *http.Request) (*http.Response, error) {
+					if got := req.Header.Get("Authorization"); got != "Bearer TOKEN" {
+						t.Errorf("Unexpected Authorization header, expected=Bearer TOKEN, got=%v", got)
+					}
+					return &http.Response{
+						StatusCode: 200,
+						Body:       ioutil.NopCloser(&bytes.Buffer{}),
+					}, nil
+				},
+			},
+			Header: http.Header{"Authorization": []string{"Bearer TOKEN"}},
+		}
+
+		if _, err := u.Resolve(context.Background(), "http://example.com/FILE.md"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Conditional requests
+	{
+		u := URL{
+			HttpGetter: &mockHttpGetter{
+				doFn: func(req *http.Request) (*http.Response, error) {
+					if got := req.Header.Get("If-None-Match"); got != "ETAG" {
+						t.Errorf("Unexpected If-None-Match header, expected=ETAG, got=%v", got)
+					}
+					if got := req.Header.Get("If-Modified-Since"); got != "LAST MODIFIED" {
+						t.Errorf("Unexpected If-Modified-Since header, expected=LAST MODIFIED, got=%v", got)
+					}
+					return &http.Response{
+						StatusCode: 304,
+						Body:       ioutil.NopCloser(&bytes.Buffer{}),
+					}, nil
+				},
+			},
+		}
+
+		e := CacheEntry{Source: "http://example.com/FILE.md", ETag: "ETAG", LastModified: "LAST MODIFIED"}
+		ctx := context.WithValue(context.Background(), validatorsKey{}, e)
+		if _, err := u.Resolve(ctx, "http://example.com/FILE.md"); err != ErrNotModified {
+			t.Errorf("Unexpected error for unmodified file, expected=%v, got=%v", ErrNotModified, err)
+		}
+	}
+
 	// HTTP error
 	{
 		expectErr := errors.New("sample error")
@@ -169,9 +283,113 @@ func TestURL_Resolve(t *testing.T) {
 			return nil, expectErr
 		}
 
-		_, err := u.Resolve("URL")
+		_, err := u.Resolve(context.Background(), "http://example.com/FILE.md")
 		if err != expectErr {
 			t.Errorf("Unexpected error for http error, expected=%v, got=%v", expectErr, err)
 		}
 	}
+
+	// Validators
+	{
+		m.getFn = func(url string) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: 200,
+				Header: http.Header{
+					"Etag":          []string{"ETAG"},
+					"Last-Modified": []string{"LAST MODIFIED"},
+				},
+				Body: ioutil.NopCloser(&bytes.Buffer{}),
+			}, nil
+		}
+
+		rc, err := u.Resolve(context.Background(), "https://example.com/FILE.md")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, ok := rc.(*Response)
+		if !ok {
+			t.Fatalf("Unexpected content type, expected=*Response, got=%T", rc)
+		}
+		if res.ETag != "ETAG" || res.LastModified != "LAST MODIFIED" {
+			t.Errorf("Unexpected validators, expected=ETAG/LAST MODIFIED, got=%v/%v", res.ETag, res.LastModified)
+		}
+		if res.Source() != "https://example.com/FILE.md" {
+			t.Errorf("Unexpected source, expected=https://example.com/FILE.md, got=%v", res.Source())
+		}
+	}
+}
+
+func TestURL_Resolve_invalidPath(t *testing.T) {
+	u := URL{
+		HttpGetter: &mockHttpGetter{
+			getFn: func(url string) (*http.Response, error) {
+				t.Fatalf("Unexpected request for url %v", url)
+				return nil, nil
+			},
+		},
+	}
+
+	tests := []string{
+		"",
+		"./README.md",
+		"github.com/KyleBanks/kurz",
+		"ftp://example.com/README.md",
+	}
+
+	for idx, path := range tests {
+		if _, err := u.Resolve(context.Background(), path); err != ErrInvalidPath {
+			t.Errorf("[%d] Unexpected err, expected=%v, got=%v", idx, ErrInvalidPath, err)
+		}
+	}
+}
+
+func TestDefaultHttpGetter_redirect(t *testing.T) {
+	var headers []http.Header
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header)
+	}))
+	defer other.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/same":
+			http.Redirect(w, r, "/target", http.StatusFound)
+		case "/other":
+			http.Redirect(w, r, other.URL+"/target", http.StatusFound)
+		default:
+			headers = append(headers, r.Header)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		path       string
+		expectAuth bool
+	}{
+		{"/same", true},
+		{"/other", false},
+	}
+
+	for idx, tt := range tests {
+		headers = nil
+		u := URL{Header: http.Header{}}
+		u.Header.Set("Authorization", "Bearer TOKEN")
+		u.Header.Set("PRIVATE-TOKEN", "TOKEN")
+
+		res, err := u.Resolve(context.Background(), srv.URL+tt.path)
+		if err != nil {
+			t.Fatalf("[%d] Unexpected error, got=%v", idx, err)
+		}
+		res.Close()
+
+		if len(headers) != 1 {
+			t.Fatalf("[%d] Expected the redirect to be followed, got=%v requests", idx, len(headers))
+		}
+		for _, h := range []string{"Authorization", "PRIVATE-TOKEN"} {
+			if got := headers[0].Get(h) != ""; got != tt.expectAuth {
+				t.Errorf("[%d] Unexpected %v header, expected=%v, got=%q", idx, h, tt.expectAuth, headers[0].Get(h))
+			}
+		}
+	}
 }
@@ -0,0 +1,42 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+// StdinPath is the path that refers to standard input.
+const StdinPath = "-"
+
+// Stdin can be used to resolve a document from a stream such as
+// standard input, using the StdinPath.
+//
+// The stream is read in full the first time it's resolved, and the same
+// content is returned each time it's resolved thereafter.
+type Stdin struct {
+	Reader io.Reader
+
+	once    sync.Once
+	content []byte
+	err     error
+}
+
+// Resolve reads the content of the stream, returning ErrInvalidPath
+// for any path other than the StdinPath.
+func (s *Stdin) Resolve(ctx context.Context, path string) (io.ReadCloser, error) {
+	if path != StdinPath {
+		return nil, ErrInvalidPath
+	}
+
+	s.once.Do(func() {
+		s.content, s.err = ioutil.ReadAll(s.Reader)
+	})
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return ioutil.NopCloser(bytes.NewReader(s.content)), nil
+}
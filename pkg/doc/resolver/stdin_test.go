@@ -0,0 +1,30 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+)
+
+func TestStdin_Resolve(t *testing.T) {
+	expect := "# Piped Markdown"
+	s := Stdin{Reader: bytes.NewBufferString(expect)}
+
+	// The content is available each time it's resolved
+	for i := 0; i < 2; i++ {
+		rc, err := s.Resolve(context.Background(), StdinPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, _ := ioutil.ReadAll(rc)
+		if string(res) != expect {
+			t.Errorf("[%d] Unexpected content, expected=%v, got=%s", i, expect, res)
+		}
+	}
+
+	if _, err := s.Resolve(context.Background(), "README.md"); err != ErrInvalidPath {
+		t.Errorf("Unexpected error for other path, expected=%v, got=%v", ErrInvalidPath, err)
+	}
+}
@@ -0,0 +1,50 @@
+package resolver
+
+import (
+	"os"
+	"time"
+)
+
+// Watch polls the local file at the path, sending on the returned channel
+// each time its modification time or size changes, until the stop channel
+// is closed.
+//
+// Errors reading the file, such as while it is being replaced by an editor,
+// are ignored until the file is available again.
+func Watch(path string, interval time.Duration, stop <-chan struct{}) <-chan struct{} {
+	changes := make(chan struct{}, 1)
+
+	go func() {
+		defer close(changes)
+
+		last, _ := os.Stat(path)
+		t := time.NewTicker(interval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-t.C:
+			}
+
+			fi, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			if last != nil && fi.ModTime().Equal(last.ModTime()) && fi.Size() == last.Size() {
+				continue
+			}
+			last = fi
+
+			// Changes that occur before the previous one has been received
+			// are coalesced.
+			select {
+			case changes <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return changes
+}
@@ -0,0 +1,47 @@
+package resolver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kurz-watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "README.md")
+	if err := ioutil.WriteFile(path, []byte("# Title"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	stop := make(chan struct{})
+	changes := Watch(path, time.Millisecond*5, stop)
+
+	// No changes
+	select {
+	case <-changes:
+		t.Fatal("Unexpected change before the file was modified")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	// Modified content
+	if err := ioutil.WriteFile(path, []byte("# Updated Title"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changes:
+	case <-time.After(time.Second):
+		t.Fatal("Expected a change after the file was modified")
+	}
+
+	// Stopping closes the channel
+	close(stop)
+	for range changes {
+	}
+}
@@ -0,0 +1,239 @@
+package doc
+
+import (
+	"bytes"
+	"strings"
+)
+
+// SectionKind is the type of block that a Section represents.
+type SectionKind int
+
+const (
+	ParagraphSection SectionKind = iota
+	ListSection
+	CodeSection
+	QuoteSection
+	TableSection
+	RuleSection
+)
+
+// ruleWidth is the number of characters used to render a RuleSection.
+const ruleWidth = 40
+
+var sectionKindNames = map[SectionKind]string{
+	ParagraphSection: "paragraph",
+	ListSection:      "list",
+	CodeSection:      "code",
+	QuoteSection:     "quote",
+	TableSection:     "table",
+	RuleSection:      "rule",
+}
+
+// String returns the name of the SectionKind.
+func (k SectionKind) String() string {
+	return sectionKindNames[k]
+}
+
+// style returns the default Style of the section's spans.
+func (k SectionKind) style() Style {
+	switch k {
+	case CodeSection:
+		return CodeBlock
+	case QuoteSection:
+		return BlockQuote
+	default:
+		return Normal
+	}
+}
+
+// Section is a single block of content within a Header, such as
+// a paragraph or code block.
+type Section struct {
+	Kind  SectionKind
+	Spans []Span
+
+	// Lang is the language of a CodeSection.
+	Lang string
+
+	// Table is the content of a TableSection.
+	Table *Table
+
+	// width limits the width of a Table, see Fit.
+	width int
+}
+
+// Span is a run of inline text with a Style, followed by its children.
+type Span struct {
+	Text     string
+	Style    Style
+	Children []Span
+
+	// Link is the destination of a Link or Image span.
+	Link string
+}
+
+// LinkRef is a link or image within the text of a Section.
+type LinkRef struct {
+	Destination string
+
+	// Start and End are the offsets of the link within the Section's text.
+	Start int
+	End   int
+}
+
+// run is a piece of text with the final Style it's rendered with.
+type run struct {
+	text  string
+	style Style
+
+	// link is the index of the run's link span, or -1.
+	link int
+}
+
+// Fit returns a copy of the section with its Table laid out within the width.
+func (s Section) Fit(width int) Section {
+	s.width = width
+	return s
+}
+
+// Text returns the plain text of the section, without any styling applied.
+func (s Section) Text() string {
+	var buf bytes.Buffer
+	for _, r := range s.runs() {
+		buf.WriteString(r.text)
+	}
+	return buf.String()
+}
+
+// Render returns the text of the section with the Styler applied.
+func (s Section) Render(st Styler) string {
+	indent := st.Indent(s.Kind.style())
+
+	var buf bytes.Buffer
+	lineStart := true
+	for _, r := range s.runs() {
+		lines := strings.Split(r.text, "\n")
+		for i, l := range lines {
+			if i > 0 {
+				buf.WriteString("\n")
+				lineStart = true
+			}
+			if len(l) == 0 {
+				continue
+			}
+
+			if lineStart {
+				buf.WriteString(indent)
+				lineStart = false
+			}
+			buf.WriteString(st.Style(l, r.style))
+		}
+	}
+	return buf.String()
+}
+
+// Links returns the links within the section, in the order they
+// appear in its text.
+func (s Section) Links() []LinkRef {
+	var links []LinkRef
+	var offset int
+	for _, r := range s.runs() {
+		if r.link >= 0 {
+			// A link around an image, such as a badge, is numbered
+			// before the image but its text follows the image's, and
+			// links within a wrapped table cell are interrupted by the
+			// text of other cells, so links don't always appear in order.
+			for len(links) <= r.link {
+				links = append(links, LinkRef{Start: -1})
+			}
+			if links[r.link].Start < 0 {
+				links[r.link].Start = offset
+			}
+			links[r.link].End = offset + len(r.text)
+		}
+		offset += len(r.text)
+	}
+
+	dests := s.linkDestinations(nil, s.Spans)
+	if s.Table != nil {
+		for _, row := range s.Table.Rows {
+			for _, cell := range row.Cells {
+				dests = s.linkDestinations(dests, cell)
+			}
+		}
+	}
+	for i := range links {
+		links[i].Destination = dests[i]
+	}
+	return links
+}
+
+// linkDestinations appends the destinations of the link spans in order.
+func (s Section) linkDestinations(dests []string, spans []Span) []string {
+	for _, sp := range spans {
+		if sp.isLink() {
+			dests = append(dests, sp.Link)
+		}
+		dests = s.linkDestinations(dests, sp.Children)
+	}
+	return dests
+}
+
+// runs flattens the spans of the section into runs of text.
+func (s Section) runs() []run {
+	var runs []run
+	if s.Kind == RuleSection {
+		runs = append(runs, run{strings.Repeat("─", ruleWidth), Normal, -1})
+	}
+
+	var links int
+	if s.Table != nil {
+		runs = append(runs, s.Table.runs(s.width, &links)...)
+	}
+	for _, sp := range s.Spans {
+		runs = sp.appendRuns(runs, s.Kind.style(), -1, &links)
+	}
+
+	if len(runs) == 0 || !strings.HasSuffix(runs[len(runs)-1].text, "\n") {
+		runs = append(runs, run{"\n", Normal, -1})
+	}
+	return runs
+}
+
+// appendRuns appends the runs of the span and its children, numbering
+// each link span with the links counter.
+func (sp Span) appendRuns(runs []run, inherited Style, link int, links *int) []run {
+	style := sp.Style
+	if style == Normal {
+		style = inherited
+	}
+	if sp.isLink() {
+		link = *links
+		*links++
+	}
+
+	if sp.Style == Image {
+		runs = append(runs, run{"Image: ", style, link})
+	}
+	if len(sp.Text) > 0 {
+		runs = append(runs, run{sp.Text, style, link})
+	}
+	for _, c := range sp.Children {
+		runs = c.appendRuns(runs, style, link, links)
+	}
+
+	if sp.isLink() {
+		dest := "<" + sp.Link + ">"
+		if len(sp.Text) > 0 || len(sp.Children) > 0 {
+			dest = " " + dest
+		}
+		runs = append(runs, run{dest, style, link})
+	}
+
+	return runs
+}
+
+// isLink returns true if the span is a Link or Image with a destination.
+func (sp Span) isLink() bool {
+	return len(sp.Link) > 0 && (sp.Style == Link || sp.Style == Image)
+}
@@ -0,0 +1,119 @@
+package doc
+
+import (
+	"fmt"
+	"testing"
+)
+
+type mockStyler struct{}
+
+func (mockStyler) Style(s string, st Style) string {
+	return fmt.Sprintf("<%d>%s</%d>", st, s, st)
+}
+
+func (mockStyler) Indent(st Style) string {
+	if st == BlockQuote {
+		return "> "
+	}
+	return ""
+}
+
+func TestSection_Text(t *testing.T) {
+	tests := []struct {
+		s      Section
+		expect string
+	}{
+		{Section{}, "\n"},
+		{Section{Spans: []Span{{Text: "Text\n"}}}, "Text\n"},
+		{Section{Spans: []Span{{Text: "Some "}, {Style: Bold, Children: []Span{{Text: "bold"}}}}}, "Some bold\n"},
+		{Section{Spans: []Span{{Style: Link, Link: "http://example.com", Children: []Span{{Text: "link"}}}}}, "link <http://example.com>\n"},
+		{Section{Spans: []Span{{Style: Image, Link: "image.png"}}}, "Image: <image.png>\n"},
+		{Section{Kind: RuleSection}, "────────────────────────────────────────\n"},
+	}
+
+	for idx, tt := range tests {
+		if got := tt.s.Text(); got != tt.expect {
+			t.Errorf("[%d] Unexpected text, expected=%q, got=%q", idx, tt.expect, got)
+		}
+	}
+}
+
+func TestSection_Render(t *testing.T) {
+	tests := []struct {
+		s      Section
+		expect string
+	}{
+		{
+			Section{Spans: []Span{{Text: "Some "}, {Style: Bold, Children: []Span{{Text: "bold"}}}}},
+			"<0>Some </0><20>bold</20>\n",
+		},
+		{
+			Section{Kind: QuoteSection, Spans: []Span{{Text: "One "}, {Text: "Two", Style: Code}, {Text: "\nThree"}}},
+			"> <1600>One </1600><3200>Two</3200>\n> <1600>Three</1600>\n",
+		},
+	}
+
+	for idx, tt := range tests {
+		if got := tt.s.Render(mockStyler{}); got != tt.expect {
+			t.Errorf("[%d] Unexpected render, expected=%q, got=%q", idx, tt.expect, got)
+		}
+	}
+
+	// The NopStyler renders the plain text
+	s := Section{Kind: QuoteSection, Spans: []Span{{Text: "Quote\n"}}}
+	if got := s.Render(NopStyler{}); got != s.Text() {
+		t.Errorf("Unexpected NopStyler render, expected=%q, got=%q", s.Text(), got)
+	}
+}
+
+func TestSection_Links(t *testing.T) {
+	s := Section{Spans: []Span{
+		{Text: "See "},
+		{Style: Link, Link: "guide.md", Children: []Span{{Text: "the "}, {Text: "guide", Style: Bold}}},
+		{Text: " and "},
+		{Style: Image, Link: "logo.png"},
+	}}
+
+	expect := []LinkRef{
+		{Destination: "guide.md", Start: 4, End: 24},
+		{Destination: "logo.png", Start: 29, End: 46},
+	}
+
+	got := s.Links()
+	if len(got) != len(expect) {
+		t.Fatalf("Unexpected link count, expected=%v, got=%v", len(expect), len(got))
+	}
+	for i := range expect {
+		if got[i] != expect[i] {
+			t.Errorf("[%d] Unexpected link, expected=%+v, got=%+v", i, expect[i], got[i])
+		}
+	}
+
+	text := s.Text()
+	if link := text[got[0].Start:got[0].End]; link != "the guide <guide.md>" {
+		t.Errorf("Unexpected link text, expected=the guide <guide.md>, got=%v", link)
+	}
+}
+
+func TestSection_Links_linkedImage(t *testing.T) {
+	s := Section{Spans: []Span{
+		{Style: Link, Link: "https://ci", Children: []Span{
+			{Style: Image, Link: "badge.svg", Children: []Span{{Text: "build"}}},
+		}},
+	}}
+
+	expect := []LinkRef{
+		{Destination: "https://ci", Start: 24, End: 37},
+		{Destination: "badge.svg", Start: 0, End: 24},
+	}
+
+	got := s.Links()
+	if len(got) != len(expect) {
+		t.Fatalf("Unexpected link count, expected=%v, got=%v", len(expect), len(got))
+	}
+	for i := range expect {
+		if got[i] != expect[i] {
+			t.Errorf("[%d] Unexpected link, expected=%+v, got=%+v", i, expect[i], got[i])
+		}
+	}
+}
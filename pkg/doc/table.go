@@ -0,0 +1,323 @@
+package doc
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Alignment is the horizontal alignment of a table column.
+type Alignment int
+
+const (
+	AlignLeft Alignment = iota
+	AlignCenter
+	AlignRight
+)
+
+const (
+	// columnSeparator is rendered between the columns of a table.
+	columnSeparator = " │ "
+
+	// minColumnWidth is the narrowest a column is shrunk to when
+	// fitting a table within a width.
+	minColumnWidth = 3
+)
+
+// Table is the content of a TableSection.
+type Table struct {
+	// Align contains the alignment of each column.
+	Align []Alignment
+	Rows  []TableRow
+}
+
+// TableRow is a single row of a Table, containing the spans of each cell.
+type TableRow struct {
+	Header bool
+	Cells  [][]Span
+}
+
+// columns returns the number of columns in the table.
+func (t Table) columns() int {
+	cols := len(t.Align)
+	for _, r := range t.Rows {
+		if len(r.Cells) > cols {
+			cols = len(r.Cells)
+		}
+	}
+	return cols
+}
+
+// alignment returns the Alignment of a column.
+func (t Table) alignment(col int) Alignment {
+	if col < len(t.Align) {
+		return t.Align[col]
+	}
+	return AlignLeft
+}
+
+// runs lays out the table as a grid of aligned columns, with header rows
+// rendered in Bold and separated from the body by a line. If the width is
+// greater than zero, columns are narrowed and their cells wrapped so that
+// each line fits within it.
+func (t Table) runs(width int, links *int) []run {
+	cols := t.columns()
+	if cols == 0 {
+		return nil
+	}
+
+	cells := make([][][]run, len(t.Rows))
+	widths := make([]int, cols)
+	for r, row := range t.Rows {
+		style := Normal
+		if row.Header {
+			style = Bold
+		}
+
+		cells[r] = make([][]run, cols)
+		for c, spans := range row.Cells {
+			var rs []run
+			for _, sp := range spans {
+				rs = sp.appendRuns(rs, style, -1, links)
+			}
+			cells[r][c] = rs
+
+			for _, l := range strings.Split(runsText(rs), "\n") {
+				if w := runewidth.StringWidth(l); w > widths[c] {
+					widths[c] = w
+				}
+			}
+		}
+	}
+
+	if width > 0 {
+		fitColumns(widths, width-len([]rune(columnSeparator))*(cols-1))
+	}
+
+	var runs []run
+	for r, row := range t.Rows {
+		lines := make([][][]run, cols)
+		var height int
+		for c := range lines {
+			lines[c] = wrapRuns(cells[r][c], widths[c])
+			if len(lines[c]) > height {
+				height = len(lines[c])
+			}
+		}
+
+		for l := 0; l < height; l++ {
+			for c := 0; c < cols; c++ {
+				var line []run
+				if l < len(lines[c]) {
+					line = lines[c][l]
+				}
+
+				last := c == cols-1
+				if c > 0 {
+					sep := columnSeparator
+					if last && len(line) == 0 {
+						sep = strings.TrimRight(sep, " ")
+					}
+					runs = append(runs, run{sep, Normal, -1})
+				}
+				runs = append(runs, alignRuns(line, widths[c], t.alignment(c), last)...)
+			}
+			runs = append(runs, run{"\n", Normal, -1})
+		}
+
+		if row.Header && (r == len(t.Rows)-1 || !t.Rows[r+1].Header) {
+			rules := make([]string, cols)
+			for c, w := range widths {
+				rules[c] = strings.Repeat("─", w)
+			}
+			runs = append(runs, run{strings.Join(rules, "─┼─") + "\n", Normal, -1})
+		}
+	}
+
+	return runs
+}
+
+// fitColumns narrows the widest columns until their total width fits
+// within the available width, or each reaches the minColumnWidth.
+func fitColumns(widths []int, available int) {
+	total := 0
+	for _, w := range widths {
+		total += w
+	}
+
+	for total > available {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			return
+		}
+
+		widths[widest]--
+		total--
+	}
+}
+
+// alignRuns pads a line of runs to the width according to the Alignment.
+// Trailing padding is omitted from the last column.
+func alignRuns(line []run, width int, align Alignment, last bool) []run {
+	pad := width - runewidth.StringWidth(runsText(line))
+	if pad <= 0 || (last && len(line) == 0) {
+		return line
+	}
+
+	var left, right int
+	switch align {
+	case AlignCenter:
+		left = pad / 2
+		right = pad - left
+	case AlignRight:
+		left = pad
+	default:
+		right = pad
+	}
+	if last {
+		right = 0
+	}
+
+	var runs []run
+	if left > 0 {
+		runs = append(runs, run{strings.Repeat(" ", left), Normal, -1})
+	}
+	runs = append(runs, line...)
+	if right > 0 {
+		runs = append(runs, run{strings.Repeat(" ", right), Normal, -1})
+	}
+	return runs
+}
+
+// wrapRuns splits the runs into lines no wider than the width, breaking at
+// spaces and newlines where possible. Words wider than the width are split
+// across lines.
+func wrapRuns(runs []run, width int) [][]run {
+	var lines [][]run
+	var line, space, word []run
+	var lineWidth, spaceWidth, wordWidth int
+
+	breakLine := func() {
+		lines = append(lines, line)
+		line, space = nil, nil
+		lineWidth, spaceWidth = 0, 0
+	}
+	flushWord := func() {
+		if len(word) == 0 {
+			return
+		}
+		if lineWidth > 0 && lineWidth+spaceWidth+wordWidth > width {
+			breakLine()
+		}
+		if lineWidth > 0 {
+			line = append(line, space...)
+			lineWidth += spaceWidth
+		}
+		space, spaceWidth = nil, 0
+
+		for wordWidth > width-lineWidth && width > 0 {
+			head, tail := splitRuns(word, width-lineWidth)
+			line = append(line, head...)
+			breakLine()
+			word = tail
+			wordWidth = runewidth.StringWidth(runsText(word))
+		}
+		line = append(line, word...)
+		lineWidth += wordWidth
+		word, wordWidth = nil, 0
+	}
+
+	for _, r := range runs {
+		for _, piece := range splitWords(r.text) {
+			w := runewidth.StringWidth(piece)
+			switch {
+			case piece == "\n":
+				flushWord()
+				breakLine()
+			case strings.TrimSpace(piece) == "":
+				flushWord()
+				space = append(space, run{piece, r.style, r.link})
+				spaceWidth += w
+			default:
+				word = append(word, run{piece, r.style, r.link})
+				wordWidth += w
+			}
+		}
+	}
+	flushWord()
+
+	if len(line) > 0 || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// splitWords splits text into words, runs of spaces and newlines.
+func splitWords(text string) []string {
+	var pieces []string
+	start := 0
+	for i, r := range text {
+		if i == start {
+			continue
+		}
+
+		prev := rune(text[i-1])
+		if r == '\n' || prev == '\n' || (r == ' ') != (prev == ' ') {
+			pieces = append(pieces, text[start:i])
+			start = i
+		}
+	}
+	if start < len(text) {
+		pieces = append(pieces, text[start:])
+	}
+	return pieces
+}
+
+// splitRuns splits the runs after the provided display width.
+func splitRuns(runs []run, width int) (head []run, tail []run) {
+	for i, r := range runs {
+		w := runewidth.StringWidth(r.text)
+		if w <= width {
+			head = append(head, r)
+			width -= w
+			continue
+		}
+
+		var cut int
+		for j, c := range r.text {
+			cw := runewidth.RuneWidth(c)
+			if cw > width {
+				cut = j
+				break
+			}
+			width -= cw
+		}
+		if cut == 0 && len(head) == 0 {
+			// Always make progress, even if a single character is
+			// wider than the width.
+			_, size := utf8.DecodeRuneInString(r.text)
+			cut = size
+		}
+		if cut > 0 {
+			head = append(head, run{r.text[:cut], r.style, r.link})
+		}
+		tail = append([]run{{r.text[cut:], r.style, r.link}}, runs[i+1:]...)
+		return head, tail
+	}
+	return head, nil
+}
+
+// runsText returns the concatenated text of the runs.
+func runsText(runs []run) string {
+	var text string
+	for _, r := range runs {
+		text += r.text
+	}
+	return text
+}
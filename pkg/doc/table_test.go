@@ -0,0 +1,113 @@
+package doc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTable_layout(t *testing.T) {
+	table := &Table{
+		Align: []Alignment{AlignLeft, AlignCenter, AlignRight},
+		Rows: []TableRow{
+			{Header: true, Cells: [][]Span{{{Text: "Name"}}, {{Text: "Kind"}}, {{Text: "Size"}}}},
+			{Cells: [][]Span{{{Text: "kurz"}}, {{Text: "cli"}}, {{Text: "12"}}}},
+			{Cells: [][]Span{{{Text: "a longer name"}}, {{Text: "x"}}, {{Text: "1024"}}}},
+		},
+	}
+
+	tests := []struct {
+		width  int
+		expect string
+	}{
+		{
+			0,
+			"Name          │ Kind │ Size\n" +
+				"──────────────┼──────┼─────\n" +
+				"kurz          │ cli  │   12\n" +
+				"a longer name │  x   │ 1024\n",
+		},
+		{
+			22,
+			"Name     │ Kind │ Size\n" +
+				"─────────┼──────┼─────\n" +
+				"kurz     │ cli  │   12\n" +
+				"a longer │  x   │ 1024\n" +
+				"name     │      │\n",
+		},
+		{
+			14,
+			"Nam │ Kin │ Siz\n" +
+				"e   │  d  │   e\n" +
+				"────┼─────┼────\n" +
+				"kur │ cli │  12\n" +
+				"z   │     │\n" +
+				"a   │  x  │ 102\n" +
+				"lon │     │   4\n" +
+				"ger │     │\n" +
+				"nam │     │\n" +
+				"e   │     │\n",
+		},
+	}
+
+	for idx, tt := range tests {
+		s := Section{Kind: TableSection, Table: table}.Fit(tt.width)
+		if got := s.Text(); got != tt.expect {
+			t.Errorf("[%d] Unexpected text, expected=\n%v\ngot=\n%v", idx, tt.expect, got)
+		}
+	}
+}
+
+func TestTable_links(t *testing.T) {
+	s := Section{Kind: TableSection, Table: &Table{
+		Rows: []TableRow{
+			{Header: true, Cells: [][]Span{{{Text: "Link"}}}},
+			{Cells: [][]Span{{{Style: Link, Link: "a.md", Children: []Span{{Text: "A"}}}}}},
+		},
+	}}
+
+	links := s.Links()
+	if len(links) != 1 || links[0].Destination != "a.md" {
+		t.Fatalf("Unexpected links, got=%v", links)
+	}
+	if got := s.Text()[links[0].Start:links[0].End]; got != "A <a.md>" {
+		t.Errorf("Unexpected link text, expected=A <a.md>, got=%v", got)
+	}
+}
+
+func TestTable_links_wrapped(t *testing.T) {
+	s := Section{Kind: TableSection, Table: &Table{
+		Rows: []TableRow{
+			{Cells: [][]Span{
+				{{Style: Link, Link: "a.md", Children: []Span{{Text: "First"}}}},
+				{{Style: Link, Link: "b.md", Children: []Span{{Text: "Second"}}}},
+			}},
+		},
+	}}.Fit(20)
+
+	// The second cell's link is displayed between the lines of the first.
+	links := s.Links()
+	if len(links) != 2 || links[0].Destination != "a.md" || links[1].Destination != "b.md" {
+		t.Fatalf("Unexpected links, got=%v", links)
+	}
+	if links[1].Start < links[0].Start || links[1].Start > links[0].End {
+		t.Errorf("Expected the second link within the first, got=%+v", links)
+	}
+}
+
+func TestWrapRuns(t *testing.T) {
+	runs := []run{
+		{"Some ", Normal, -1},
+		{"bold", Bold, -1},
+		{"er text\nnext", Normal, -1},
+	}
+
+	var got []string
+	for _, l := range wrapRuns(runs, 10) {
+		got = append(got, runsText(l))
+	}
+
+	expect := []string{"Some", "bolder", "text", "next"}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("Unexpected lines, expected=%q, got=%q", expect, got)
+	}
+}
@@ -0,0 +1,145 @@
+package console
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+)
+
+// titleSection is the section index of a header's title, matching titleMatch.
+const titleSection = titleMatch
+
+// block is a section or, in chapter mode, a nested header's title.
+type block struct {
+	header  int
+	section int
+}
+
+// SetChapters sets whether the headers nested under the selected header
+// are displayed along with its sections.
+func (w *Window) SetChapters(enabled bool) {
+	w.chapters = enabled
+	if len(w.doc.Headers) == 0 {
+		return
+	}
+
+	var selected block
+	if w.isValidSectionIndex(w.selectedSection) {
+		selected = w.blocks[w.selectedSection]
+	}
+
+	w.renderContentBody()
+	if w.focusMode == focusContent {
+		idx := w.blockIndex(selected.header, selected.section)
+		if idx < 0 {
+			idx = 0
+		}
+		w.setSelectedSection(idx)
+	}
+	w.renderInputBar()
+}
+
+// toggleChapters switches chapter mode on or off.
+func (w *Window) toggleChapters() {
+	w.SetChapters(!w.chapters)
+}
+
+// contentBlocks returns the blocks of the selected header, followed in
+// chapter mode by those of its nested headers that aren't collapsed.
+func (w *Window) contentBlocks() []block {
+	var blocks []block
+	sections := func(header int) {
+		for i := range w.doc.Headers[header].Content {
+			blocks = append(blocks, block{header, i})
+		}
+	}
+
+	sections(w.selectedHeader)
+	if !w.chapters || w.selectedHeader >= len(w.outline.parents) {
+		return blocks
+	}
+
+	end := w.outline.end(w.selectedHeader)
+	for h := w.selectedHeader + 1; h < end; h++ {
+		blocks = append(blocks, block{h, titleSection})
+		if w.contentState.get(h, titleSection) == collapsedContent {
+			h = w.outline.end(h) - 1
+			continue
+		}
+		sections(h)
+	}
+	return blocks
+}
+
+// renderBlock returns the text of a block with its regions.
+func (w *Window) renderBlock(idx int, b block) string {
+	h := w.doc.Headers[b.header]
+	if b.section == titleSection {
+		title := h.Title
+		if h.Level > 0 {
+			title = strings.Repeat("#", h.Level) + " " + title
+		}
+
+		text := Styler{}.Style(title, doc.Bold)
+		if special := w.contentState.get(b.header, b.section); len(special) > 0 {
+			text += " " + special
+		}
+		return text + "\n"
+	}
+
+	s := h.Content[b.section]
+	marks := linkMarks(idx, s)
+	if w.search != nil {
+		marks = append(marks, w.search.marks(b.header, b.section)...)
+	}
+
+	text := s.Render(newRegionStyler(Styler{}, fmt.Sprintf("%d", idx), marks))
+	if special := w.contentState.get(b.header, b.section); len(special) > 0 {
+		text = special
+	}
+	return text
+}
+
+// blockIndex returns the index of a header's section, or -1.
+func (w *Window) blockIndex(header, section int) int {
+	for i, b := range w.blocks {
+		if b.header == header && b.section == section {
+			return i
+		}
+	}
+	return -1
+}
+
+// inChapter returns true if the header is in the selected chapter.
+func (w *Window) inChapter(header int) bool {
+	return w.chapters && header > w.selectedHeader && header < len(w.outline.parents) &&
+		w.outline.isNested(header, w.selectedHeader)
+}
+
+// expandBlock expands a header's section and the titles it's nested under,
+// returning true if anything was expanded.
+func (w *Window) expandBlock(header, section int) bool {
+	var expanded bool
+	expand := func(header, section int) {
+		if w.contentState.get(header, section) != "" {
+			w.contentState.clear(header, section)
+			expanded = true
+		}
+	}
+
+	if w.inChapter(header) {
+		for _, a := range w.outline.ancestors(header) {
+			if a > w.selectedHeader {
+				expand(a, titleSection)
+			}
+		}
+		if section != titleSection {
+			expand(header, titleSection)
+		}
+	}
+	if section != titleSection {
+		expand(header, section)
+	}
+	return expanded
+}
@@ -0,0 +1,157 @@
+package console
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+)
+
+// chapterDoc is a document with an H2 whose content is all in H3s:
+//
+//	0 Title
+//	1   Install
+//	2     Linux
+//	3       Packages
+//	4     macOS
+//	5   Usage
+var chapterDoc = doc.Document{
+	Headers: []doc.Header{
+		{Title: "Title", Level: 1, Content: []doc.Section{
+			{Spans: []doc.Span{{Text: "Intro\n"}}},
+		}},
+		{Title: "Install", Level: 2},
+		{Title: "Linux", Level: 3, Content: []doc.Section{
+			{Spans: []doc.Span{{Text: "apt install kurz\n"}}},
+			{Spans: []doc.Span{
+				{Text: "See "},
+				{Style: doc.Link, Link: "#usage", Children: []doc.Span{{Text: "usage"}}},
+			}},
+		}},
+		{Title: "Packages", Level: 4, Content: []doc.Section{
+			{Spans: []doc.Span{{Text: "deb and rpm\n"}}},
+		}},
+		{Title: "macOS", Level: 3, Content: []doc.Section{
+			{Spans: []doc.Span{{Text: "brew install kurz\n"}}},
+		}},
+		{Title: "Usage", Level: 2, Content: []doc.Section{
+			{Spans: []doc.Span{{Text: "kurz README.md\n"}}},
+		}},
+	},
+}
+
+func TestWindow_contentBlocks(t *testing.T) {
+	w := NewWindow()
+	w.RenderDocument(chapterDoc)
+	w.tableOfContents.SetCurrentItem(1)
+
+	if len(w.blocks) != 0 {
+		t.Errorf("Unexpected blocks without chapters, expected none, got=%v", w.blocks)
+	}
+
+	w.SetChapters(true)
+	expect := []block{
+		{2, titleSection}, {2, 0}, {2, 1},
+		{3, titleSection}, {3, 0},
+		{4, titleSection}, {4, 0},
+	}
+	if !reflect.DeepEqual(w.blocks, expect) {
+		t.Errorf("Unexpected blocks, expected=%v, got=%v", expect, w.blocks)
+	}
+
+	// Collapsing a title hides its sections and nested headers.
+	w.setFocusMode(focusContent)
+	w.collapseSection(0)
+	expect = []block{{2, titleSection}, {4, titleSection}, {4, 0}}
+	if !reflect.DeepEqual(w.blocks, expect) {
+		t.Errorf("Unexpected blocks after collapsing, expected=%v, got=%v", expect, w.blocks)
+	}
+
+	w.SetChapters(false)
+	if len(w.blocks) != 0 {
+		t.Errorf("Unexpected blocks after disabling chapters, expected none, got=%v", w.blocks)
+	}
+}
+
+func TestWindow_renderBlock(t *testing.T) {
+	w := NewWindow()
+	w.SetChapters(true)
+	w.RenderDocument(chapterDoc)
+	w.tableOfContents.SetCurrentItem(1)
+
+	tests := []struct {
+		block  block
+		expect string
+	}{
+		{block{2, titleSection}, "### Linux"},
+		{block{2, 0}, "apt install kurz"},
+	}
+
+	for idx, tt := range tests {
+		if got := w.renderBlock(0, tt.block); !strings.Contains(got, tt.expect) {
+			t.Errorf("[%d] Unexpected text, expected to contain %q, got=%q", idx, tt.expect, got)
+		}
+	}
+
+	w.contentState.set(2, titleSection, collapsedContent)
+	if got := w.renderBlock(0, block{2, titleSection}); !strings.Contains(got, collapsedContent) {
+		t.Errorf("Unexpected collapsed title, expected to contain %q, got=%q", collapsedContent, got)
+	}
+}
+
+func TestWindow_SetChapters_keepsSelection(t *testing.T) {
+	w := NewWindow()
+	w.RenderDocument(chapterDoc)
+	w.setFocusMode(focusContent)
+
+	w.SetChapters(true)
+	if w.selectedSection != 0 {
+		t.Errorf("Unexpected selectedSection, expected=0, got=%v", w.selectedSection)
+	}
+
+	w.setSelectedSection(5)
+	w.SetChapters(false)
+	if w.selectedSection != 0 {
+		t.Errorf("Unexpected selectedSection after disabling, expected=0, got=%v", w.selectedSection)
+	}
+}
+
+func TestWindow_selectedSectionLinks_chapters(t *testing.T) {
+	w := NewWindow()
+	w.SetChapters(true)
+	w.RenderDocument(chapterDoc)
+	w.tableOfContents.SetCurrentItem(1)
+	w.setFocusMode(focusContent)
+
+	if links := w.selectedSectionLinks(); len(links) != 0 {
+		t.Errorf("Unexpected links of a title, expected none, got=%v", links)
+	}
+
+	w.setSelectedSection(2)
+	links := w.selectedSectionLinks()
+	if len(links) != 1 || links[0].Destination != "#usage" {
+		t.Errorf("Unexpected links, expected=[#usage], got=%v", links)
+	}
+}
+
+func TestWindow_nextMatch_chapters(t *testing.T) {
+	w := NewWindow()
+	w.SetChapters(true)
+	w.RenderDocument(chapterDoc)
+	w.tableOfContents.SetCurrentItem(1)
+	w.setFocusMode(focusContent)
+	w.collapseSection(3)
+
+	w.setSearch("rpm")
+	w.nextMatch(1)
+
+	// Matches within the chapter are shown without selecting their header,
+	// expanding the collapsed title they're nested under.
+	if w.selectedHeader != 1 {
+		t.Errorf("Unexpected selectedHeader, expected=1, got=%v", w.selectedHeader)
+	}
+	if b := w.blocks[w.selectedSection]; b != (block{3, 0}) {
+		t.Errorf("Unexpected selected block, expected=%v, got=%v", block{3, 0}, b)
+	}
+}
@@ -2,12 +2,17 @@ package console
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/KyleBanks/kurz/pkg/debug"
 	"github.com/KyleBanks/kurz/pkg/doc"
+	"github.com/KyleBanks/kurz/pkg/doc/resolver"
 
 	"github.com/atotto/clipboard"
+	"github.com/gdamore/tcell"
 	"github.com/rivo/tview"
 )
 
@@ -16,46 +21,139 @@ type focusMode int
 const (
 	focusTableOfContents focusMode = iota
 	focusContent
+	focusSearch
+	focusFiles
+	focusHelp
+	focusModal
+	focusLoading
 )
 
+// Loader loads the document at a path, such as the destination of a link.
+// Loading is cancelled by the context when ESC is pressed.
+type Loader func(ctx context.Context, path string) (doc.Document, error)
+
+// progressInterval is how often the loading message is updated with the
+// progress of the document being loaded.
+const progressInterval = time.Millisecond * 100
+
 type Window struct {
 	*tview.Application
 
-	root *tview.Flex
+	pages  *tview.Pages
+	root   *tview.Flex
+	layout *tview.Grid
 
 	modal *tview.Modal
 
 	tableOfContents *tview.List
+	outline         outline
 	contentBody     *tview.TextView
+	contentView     *clickTextView
 	inputBar        *tview.TextView
+	searchField     *tview.InputField
+	fileList        *tview.List
+	helpView        *tview.TextView
+
+	// source is the document as it was rendered, and doc is the same
+	// document laid out to fit the width of the content body.
+	source doc.Document
+	doc    doc.Document
+	width  int
+
+	// reloaded is a document reloaded while an overlay was open, which is
+	// applied once the overlay closes.
+	reloaded *doc.Document
 
-	doc doc.Document
+	// maxWidth limits the width that documents are laid out to, or is
+	// zero to use the full width of the content body.
+	maxWidth int
+
+	// tableOfContentsWidth is set by dragging the divider, or zero.
+	tableOfContentsWidth int
+	resizing             bool
 
 	focusMode       focusMode
 	selectedHeader  int
 	selectedSection int
 
+	// tableOfContentsRows contains the index of the header displayed in
+	// each row of the table of contents, which omits folded headers.
+	tableOfContentsRows []int
+
+	// blocks contains the header and section displayed in each region of
+	// the content body, which selectedSection is an index of. In chapter
+	// mode, the blocks include the headers nested under the selected one.
+	blocks   []block
+	chapters bool
+
+	// selectedLink is the index of the selected link within the selected
+	// section, or -1 if no link is selected.
+	selectedLink int
+
+	// singlePage indicates that the document has no headers of its
+	// own, so the content is displayed without a table of contents.
+	singlePage bool
+
+	search *search
+
+	// status is a message displayed in the input bar until the next
+	// document is displayed, such as an error loading a link.
+	status string
+
+	loader  Loader
+	history *history
+
+	// timeout limits the time taken to load a document, and cancelLoad
+	// cancels the document currently being loaded, which is loaded with
+	// the loading context.
+	timeout    time.Duration
+	loading    context.Context
+	cancelLoad context.CancelFunc
+
+	// update runs a function on the UI goroutine, such as displaying a
+	// document once it has loaded in the background.
+	update func(func())
+
+	// dir is the directory being browsed, and files contains the path
+	// of each item in the file navigator relative to it.
+	dir   string
+	files []string
+
 	contentState *contentState
 	inputHandler *inputHandler
+
+	// keyBindings replaces the keys of inputs by their action, and
+	// keyCounts enables counts to be typed before an input.
+	keyBindings map[string]keyBinding
+	keyCounts   bool
 }
 
 func NewWindow() *Window {
 	w := Window{
 		modal:        tview.NewModal(),
 		contentState: newContentState(),
+		history:      newHistory(),
+		selectedLink: -1,
 	}
 
+	w.contentView = &clickTextView{TextView: w.ContentBody(), clicked: w.contentClicked}
+	w.layout = tview.NewGrid().
+		SetBorders(true)
+	w.renderLayout()
+
 	w.root = tview.NewFlex().
 		SetDirection(tview.FlexRow).
-		AddItem(tview.NewGrid().
-			SetBorders(true).
-			AddItem(w.TableOfContents(), 0, 0, 1, 1, 0, 0, false).
-			AddItem(w.ContentBody(), 0, 1, 1, 3, 0, 0, false),
-			0, 1, false).
+		AddItem(w.layout, 0, 1, false).
 		AddItem(w.InputBar(), 1, 1, false)
+	w.pages = tview.NewPages().
+		AddPage(mainPage, w.root, true, true)
 
 	w.Application = tview.NewApplication().
-		SetRoot(w.root, true)
+		SetRoot(w.pages, true).
+		SetAfterDrawFunc(w.afterDraw).
+		EnableMouse(true).
+		SetMouseCapture(w.handleMouse)
+	w.update = func(f func()) { w.QueueUpdateDraw(f) }
 
 	w.inputHandler = newInputHandler(&w)
 
@@ -68,14 +166,225 @@ func (w *Window) ShowMessage(msg string) {
 }
 
 func (w *Window) HideMessage() {
-	w.SetRoot(w.root, true)
+	w.SetRoot(w.pages, true)
+}
+
+// SetLoader sets the Loader used to open the destination of links.
+func (w *Window) SetLoader(l Loader) {
+	w.loader = l
+}
+
+// SetTimeout limits the time taken to load a document, or removes the
+// limit if the timeout is zero.
+func (w *Window) SetTimeout(timeout time.Duration) {
+	w.timeout = timeout
 }
 
+// SetMaxWidth limits the width that documents are laid out to, or removes
+// the limit if the width is zero.
+func (w *Window) SetMaxWidth(width int) {
+	w.maxWidth = width
+}
+
+// RenderDocument displays the document, adding it to the navigation history.
 func (w *Window) RenderDocument(d doc.Document) {
+	w.history.setHeader(w.selectedHeader)
+	w.history.push(historyEntry{
+		doc:   d,
+		state: newContentState(),
+	})
+
+	w.showDocument(d, w.history.entries[w.history.current].state)
+}
+
+// ReloadDocument replaces a document that has previously been rendered with
+// an updated version of it, such as after its file has been modified. The
+// documents are matched by their Source.
+//
+// If the document is currently displayed, it is rendered again with the
+// selected header (matched by title), selected section, collapsed sections
+// and search preserved.
+//
+// A document reloaded while the help, search, loading or an error is
+// displayed is applied once it's closed.
+//
+// ReloadDocument must be called on the UI goroutine, such as by
+// QueueUpdateDraw when the document is reloaded in the background.
+func (w *Window) ReloadDocument(d doc.Document) {
+	switch w.inputHandler.focus {
+	case focusSearch, focusHelp, focusModal, focusLoading:
+		w.reloaded = &d
+		return
+	}
+	w.reloaded = nil
+
+	for i := range w.history.entries {
+		e := &w.history.entries[i]
+		if e.doc.Source != d.Source || i == w.history.current {
+			continue
+		}
+
+		headers := matchHeaders(e.doc, d)
+		e.header = headers[e.header]
+		e.state = e.state.remap(headers)
+		e.doc = d
+	}
+
+	if w.history.current < 0 || w.source.Source != d.Source {
+		return
+	}
+
+	headers := matchHeaders(w.source, d)
+	header, ok := headers[w.selectedHeader]
+	if !ok && w.selectedHeader < len(d.Headers) {
+		header = w.selectedHeader
+	}
+	section, focus := w.selectedSection, w.focusMode
+
+	var query string
+	if w.search != nil {
+		query = w.search.query
+	}
+
+	e := &w.history.entries[w.history.current]
+	e.doc = d
+	e.header = header
+	e.state = w.contentState.remap(headers)
+	w.showDocument(d, e.state)
+
+	if header > 0 {
+		w.selectTableOfContentsHeader(header)
+	}
+	if focus == focusContent || w.singlePage {
+		w.setFocusMode(focusContent)
+		if n := len(w.blocks); section >= n {
+			section = n - 1
+		}
+		w.setSelectedSection(section)
+	}
+	if query != "" {
+		w.setSearch(query)
+	}
+}
+
+// applyReload reloads the document that was reloaded while an overlay was
+// open, if any.
+func (w *Window) applyReload() {
+	if w.reloaded != nil {
+		w.ReloadDocument(*w.reloaded)
+	}
+}
+
+// matchHeaders maps the index of each header in one version of a document
+// to the header with the same title in another version. When more than one
+// header shares a title, they are matched in the order they appear.
+func matchHeaders(from, to doc.Document) map[int]int {
+	indexes := make(map[string][]int)
+	for i, h := range to.Headers {
+		indexes[h.Title] = append(indexes[h.Title], i)
+	}
+
+	headers := make(map[int]int)
+	for i, h := range from.Headers {
+		if idx := indexes[h.Title]; len(idx) > 0 {
+			headers[i] = idx[0]
+			indexes[h.Title] = idx[1:]
+		}
+	}
+	return headers
+}
+
+// showDocument displays the document with the provided content state.
+func (w *Window) showDocument(d doc.Document, state *contentState) {
 	w.HideMessage()
 
-	w.doc = d
+	w.source = d
+	w.doc = d.Fit(w.width)
+	w.contentState = state
+	w.search = nil
+	w.status = ""
+	w.singlePage = len(d.Headers) == 1 && d.Headers[0].Preamble
+	w.selectedHeader = 0
+	w.renderLayout()
 	w.renderTableOfContents()
+	w.inputHandler.setInputs()
+
+	if w.singlePage {
+		w.setSelectedHeader(0)
+		w.setFocusMode(focusContent)
+	} else {
+		w.setFocusMode(focusTableOfContents)
+	}
+}
+
+// afterDraw refits the document when the width of the content body
+// has changed, such as when the terminal is resized.
+func (w *Window) afterDraw(screen tcell.Screen) {
+	_, _, width, _ := w.contentBody.GetInnerRect()
+	if w.maxWidth > 0 && width > w.maxWidth {
+		width = w.maxWidth
+	}
+	if width == w.width || width <= 0 {
+		return
+	}
+
+	// Drawing from within a draw would block.
+	w.setWidth(width)
+	go w.Draw()
+}
+
+// setWidth lays out the document to fit within the width, re-rendering
+// the content body and any search matches.
+func (w *Window) setWidth(width int) {
+	w.width = width
+	w.doc = w.source.Fit(width)
+	if len(w.doc.Headers) == 0 {
+		return
+	}
+
+	if w.search != nil {
+		current := w.search.current
+		w.search = newSearch(w.doc, w.search.query)
+		w.search.current = current
+	}
+
+	w.renderContentBody()
+	if w.focusMode == focusContent {
+		w.setSelectedSection(w.selectedSection)
+	}
+}
+
+// renderLayout arranges the table of contents and content body,
+// hiding the table of contents for single page documents.
+func (w *Window) renderLayout() {
+	w.layout.Clear()
+
+	if w.singlePage {
+		w.layout.AddItem(w.contentView, 0, 0, 1, 4, 0, 0, false)
+		return
+	}
+
+	// The table of contents has a quarter of the width by default.
+	w.layout.SetColumns()
+	if w.tableOfContentsWidth > 0 {
+		w.layout.SetColumns(w.tableOfContentsWidth, 0, 0, 0)
+	}
+	w.layout.
+		AddItem(w.TableOfContents(), 0, 0, 1, 1, 0, 0, false).
+		AddItem(w.contentView, 0, 1, 1, 3, 0, 0, false)
+}
+
+// leaveContent returns focus to the table of contents, or when there is
+// no table of contents to return to, the file navigator or exits.
+func (w *Window) leaveContent() {
+	if w.singlePage && w.files != nil {
+		w.showFiles()
+		return
+	} else if w.singlePage {
+		w.Stop()
+		return
+	}
+
 	w.setFocusMode(focusTableOfContents)
 }
 
@@ -89,15 +398,12 @@ func (w *Window) setFocusMode(f focusMode) {
 	case focusContent:
 		w.SetFocus(w.ContentBody())
 		w.setSelectedSection(0)
+	case focusFiles:
+		w.SetFocus(w.fileList)
 	}
 
 	w.inputHandler.setFocusMode(f)
 	w.renderInputBar()
-
-	// Draw to ensure that the highlight is updated. This is important when
-	// switching focus as the highlight functions don't always trigger a
-	// new draw.
-	w.Draw()
 }
 
 func (w *Window) TableOfContents() *tview.List {
@@ -109,22 +415,6 @@ func (w *Window) TableOfContents() *tview.List {
 	return w.tableOfContents
 }
 
-func (w *Window) renderTableOfContents() {
-	w.tableOfContents.Clear()
-	for _, h := range w.doc.Headers {
-		text := h.Title
-		if h.Level <= 2 {
-			text = Styler{}.Style(text, doc.Bold)
-		}
-
-		for i := 0; i < h.Level-1; i++ {
-			text = " " + text
-		}
-
-		w.tableOfContents.AddItem(text, "", 0, nil)
-	}
-}
-
 func (w *Window) ContentBody() *tview.TextView {
 	if w.contentBody == nil {
 		w.contentBody = tview.NewTextView().
@@ -140,20 +430,21 @@ func (w *Window) ContentBody() *tview.TextView {
 
 func (w *Window) renderContentBody() {
 	w.contentBody.Clear()
-	if w.doc.Headers == nil {
+	if len(w.doc.Headers) == 0 {
+		w.blocks = nil
 		return
 	}
 
-	var buf bytes.Buffer
-	for i, s := range w.getSelectedHeader().Content {
-		text := s.Text
-		if special := w.contentState.get(w.selectedHeader, i); len(special) > 0 {
-			text = special
-		}
+	w.blocks = w.contentBlocks()
 
-		buf.WriteString(fmt.Sprintf(`["%d"]%v[""]`, i, text))
+	// A TextView only ends a region at a tag followed by text on the same
+	// line, so each block's region ends where the next one begins.
+	var buf bytes.Buffer
+	for i, b := range w.blocks {
+		buf.WriteString(fmt.Sprintf(`["%d"]%v`, i, w.renderBlock(i, b)))
 		buf.WriteString("\n")
 	}
+	buf.WriteString(`[""]`)
 	w.contentBody.SetText(buf.String())
 	w.contentBody.ScrollToBeginning()
 }
@@ -169,8 +460,106 @@ func (w *Window) InputBar() *tview.TextView {
 }
 
 func (w *Window) renderInputBar() {
+	text := w.inputHandler.String()
+	if b := w.breadcrumb(); b != "" {
+		text = b + "   " + text
+	}
+	if w.search != nil {
+		text = w.search.String() + "   " + text
+	}
+	if w.status != "" {
+		text = tview.Escape(w.status) + "   " + text
+	}
+
 	w.inputBar.Clear()
-	w.inputBar.SetText(w.inputHandler.String())
+	w.inputBar.SetText(text)
+}
+
+func (w *Window) SearchField() *tview.InputField {
+	if w.searchField == nil {
+		w.searchField = tview.NewInputField().
+			SetLabel("/").
+			SetDoneFunc(w.searchDoneHandler)
+	}
+
+	return w.searchField
+}
+
+// startSearch replaces the input bar with the search prompt.
+func (w *Window) startSearch() {
+	w.root.RemoveItem(w.inputBar)
+	w.root.AddItem(w.SearchField(), 1, 1, false)
+
+	w.searchField.SetText("")
+	w.SetFocus(w.searchField)
+	w.inputHandler.setFocusMode(focusSearch)
+}
+
+// searchDoneHandler restores the input bar when the search prompt is
+// closed, searching for the entered query if it was submitted.
+func (w *Window) searchDoneHandler(key tcell.Key) {
+	w.root.RemoveItem(w.searchField)
+	w.root.AddItem(w.inputBar, 1, 1, false)
+	w.restoreFocus()
+
+	if key == tcell.KeyEnter {
+		w.setSearch(w.searchField.GetText())
+		w.nextMatch(1)
+	}
+}
+
+// setSearch searches the document for the query, or clears the
+// search if the query is empty.
+func (w *Window) setSearch(query string) {
+	w.search = nil
+	if query != "" {
+		w.search = newSearch(w.doc, query)
+		w.search.seek(w.selectedHeader)
+	}
+
+	w.renderContentBody()
+	w.renderInputBar()
+}
+
+// nextMatch moves to the next (or previous, if the delta is negative)
+// search match, selecting the header and section that contain it.
+func (w *Window) nextMatch(delta int) {
+	if w.search == nil {
+		return
+	}
+	defer w.renderInputBar()
+
+	m, ok := w.search.next(delta)
+	if !ok {
+		return
+	}
+
+	if m.header != w.selectedHeader && !w.inChapter(m.header) {
+		w.selectTableOfContentsHeader(m.header)
+	}
+
+	if m.section == titleMatch && m.header == w.selectedHeader {
+		if !w.singlePage {
+			w.setFocusMode(focusTableOfContents)
+		}
+		return
+	}
+
+	// Expand the section if it was collapsed so the match is visible.
+	if w.expandBlock(m.header, m.section) {
+		w.renderContentBody()
+	}
+
+	if w.focusMode != focusContent {
+		w.setFocusMode(focusContent)
+	}
+	w.selectedSection = w.blockIndex(m.header, m.section)
+	region := matchRegion(w.search.current)
+	if m.section == titleMatch {
+		region = fmt.Sprintf("%d", w.selectedSection)
+	}
+	w.contentBody.Highlight(region)
+	w.contentBody.ScrollToHighlight()
 }
 
 func (w *Window) getSelectedHeader() doc.Header {
@@ -187,7 +576,7 @@ func (w *Window) setSelectedHeader(selected int) {
 }
 
 func (w *Window) setSelectedSection(selected int) {
-	numSections := len(w.getSelectedHeader().Content)
+	numSections := len(w.blocks)
 	if selected < 0 {
 		selected = numSections - 1
 	} else if selected >= numSections {
@@ -195,19 +584,27 @@ func (w *Window) setSelectedSection(selected int) {
 	}
 
 	w.selectedSection = selected
+	w.selectedLink = -1
 	w.contentBody.Highlight(fmt.Sprintf("%d", selected))
 	w.contentBody.ScrollToHighlight()
 }
 
+// pageSection moves the selected section by the delta, stopping at the
+// first and last sections rather than wrapping around.
+func (w *Window) pageSection(delta int) {
+	w.setSelectedSection(clamp(w.selectedSection+delta, len(w.blocks)))
+}
+
 func (w *Window) collapseSection(idx int) {
 	if !w.isValidSectionIndex(idx) {
 		return
 	}
 
-	if state := w.contentState.get(w.selectedHeader, idx); state == collapsedContent {
-		w.contentState.clear(w.selectedHeader, idx)
+	b := w.blocks[idx]
+	if state := w.contentState.get(b.header, b.section); state == collapsedContent {
+		w.contentState.clear(b.header, b.section)
 	} else {
-		w.contentState.set(w.selectedHeader, idx, collapsedContent)
+		w.contentState.set(b.header, b.section, collapsedContent)
 	}
 
 	w.renderContentBody()
@@ -218,16 +615,238 @@ func (w *Window) copySection(idx int) {
 		return
 	}
 
-	// Use GetRegionText to have the formatting stripped from the
-	// content, including colors/bolding/etc.
-	text := w.contentBody.GetRegionText(fmt.Sprintf("%d", idx))
+	b := w.blocks[idx]
+	text := w.doc.Headers[b.header].Title
+	if b.section != titleSection {
+		text = w.doc.Headers[b.header].Content[b.section].Text()
+	}
 	clipboard.WriteAll(text)
 }
 
-func (w *Window) isValidSectionIndex(idx int) bool {
-	return idx >= 0 && idx < len(w.getSelectedHeader().Content)
+// selectLink moves the link selection within the selected section to the
+// next (or previous, if the delta is negative) link.
+func (w *Window) selectLink(delta int) {
+	links := w.selectedSectionLinks()
+	if len(links) == 0 {
+		return
+	}
+
+	selected := w.selectedLink + delta
+	if w.selectedLink < 0 && delta < 0 {
+		selected = len(links) - 1
+	}
+	selected %= len(links)
+	if selected < 0 {
+		selected += len(links)
+	}
+
+	w.selectedLink = selected
+	w.contentBody.Highlight(linkRegion(w.selectedSection, selected))
+	w.contentBody.ScrollToHighlight()
+}
+
+// openLink follows the selected link, or the first link in the selected
+// section if no link is selected.
+func (w *Window) openLink() {
+	links := w.selectedSectionLinks()
+	if len(links) == 0 {
+		return
+	}
+
+	selected := w.selectedLink
+	if selected < 0 {
+		selected = 0
+	}
+	w.followLink(links[selected].Destination)
+}
+
+// selectedSectionLinks returns the links within the selected section,
+// or nil if the section is collapsed.
+func (w *Window) selectedSectionLinks() []doc.LinkRef {
+	if !w.isValidSectionIndex(w.selectedSection) {
+		return nil
+	}
+
+	b := w.blocks[w.selectedSection]
+	if b.section == titleSection || w.contentState.get(b.header, b.section) != "" {
+		return nil
+	}
+	return w.doc.Headers[b.header].Content[b.section].Links()
+}
+
+// followLink navigates to the destination of a link. Links to an anchor
+// within the current document select the matching header, and all other
+// links are resolved relative to the document and loaded in the background.
+func (w *Window) followLink(dest string) {
+	target, anchor := dest, ""
+	if idx := strings.Index(dest, "#"); idx >= 0 {
+		target, anchor = dest[:idx], dest[idx+1:]
+	}
+
+	if target == "" {
+		idx := w.doc.FindAnchor(anchor)
+		if idx < 0 {
+			w.setStatus(fmt.Sprintf("No header found for #%v", anchor))
+			return
+		}
+
+		w.history.setHeader(w.selectedHeader)
+		w.history.push(historyEntry{doc: w.source, header: idx, state: w.contentState})
+		w.showHeader(idx)
+		return
+	}
+
+	w.Open(resolver.Join(w.doc.Source, target), anchor)
+}
+
+// Open loads and displays the document at the path using the Loader,
+// selecting the header matching the name if one is provided. See
+// SelectHeader.
+//
+// The progress of loading the document is displayed until it completes,
+// or is cancelled with ESC.
+func (w *Window) Open(path, header string) {
+	if w.loader == nil {
+		w.setStatus(fmt.Sprintf("Unable to open %v", path))
+		return
+	}
+
+	ctx := w.startLoading(path)
+	w.Go(func() { w.load(ctx, path, header) })
+}
+
+// Go runs the function in a goroutine, stopping the application to restore
+// the terminal before the program exits if it panics.
+func (w *Window) Go(fn func()) {
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				w.Stop()
+				panic(p)
+			}
+		}()
+		fn()
+	}()
 }
 
-func (w *Window) tableOfContentsSelectionHandler(index int, mainText, secondaryText string, shortcut rune) {
-	w.setSelectedHeader(index)
+// startLoading displays the loading message for the path, returning the
+// context used to load it. The context reports progress to the message.
+func (w *Window) startLoading(path string) context.Context {
+	w.stopLoading()
+
+	var ctx context.Context
+	if w.timeout > 0 {
+		ctx, w.cancelLoad = context.WithTimeout(context.Background(), w.timeout)
+	} else {
+		ctx, w.cancelLoad = context.WithCancel(context.Background())
+	}
+
+	msg := fmt.Sprintf("Loading %v...", path)
+	w.ShowMessage(msg + "\n\nPress ESC to cancel")
+	w.inputHandler.setFocusMode(focusLoading)
+
+	// Progress is reported for each chunk of a download, so the message
+	// is only updated periodically.
+	var reported time.Time
+	w.loading = doc.WithProgress(ctx, func(progress string) {
+		if time.Since(reported) < progressInterval {
+			return
+		}
+		reported = time.Now()
+
+		w.update(func() {
+			if ctx.Err() == nil {
+				w.modal.SetText(fmt.Sprintf("%v\n\n%v\n\nPress ESC to cancel", msg, progress))
+			}
+		})
+	})
+	return w.loading
+}
+
+// stopLoading cancels the document being loaded, if there is one.
+func (w *Window) stopLoading() {
+	if w.cancelLoad != nil {
+		w.cancelLoad()
+		w.cancelLoad = nil
+	}
+	w.loading = nil
+}
+
+// cancelLoading stops loading the document and displays the previous view
+// again, exiting if there isn't one.
+func (w *Window) cancelLoading() {
+	w.stopLoading()
+	if w.source.Headers == nil && w.files == nil {
+		w.Stop()
+		return
+	}
+
+	w.HideMessage()
+	w.restoreFocus()
+}
+
+// load loads the document at the path using the Loader, and displays it
+// on the UI goroutine unless loading was cancelled in the meantime.
+func (w *Window) load(ctx context.Context, path, header string) {
+	d, err := w.loader(ctx, path)
+	w.update(func() {
+		if ctx != w.loading {
+			return
+		}
+
+		w.stopLoading()
+		if err != nil {
+			w.ShowError(err, func() { w.Open(path, header) })
+			return
+		}
+
+		w.RenderDocument(d)
+		if header != "" {
+			w.SelectHeader(header)
+		}
+		w.applyReload()
+	})
+}
+
+// navigate moves back (if the delta is negative) or forward through the
+// history of displayed documents.
+func (w *Window) navigate(delta int) {
+	w.history.setHeader(w.selectedHeader)
+	e, ok := w.history.move(delta)
+	if !ok {
+		return
+	}
+
+	w.showDocument(e.doc, e.state)
+	if e.header > 0 {
+		w.showHeader(e.header)
+	}
+}
+
+// SelectHeader displays the header of the current document matching the
+// name, which is either its anchor or its title. See doc.Document.FindHeader.
+func (w *Window) SelectHeader(name string) {
+	idx := w.source.FindHeader(name)
+	if idx < 0 {
+		w.setStatus(fmt.Sprintf("No header found for '%v'", name))
+		return
+	}
+
+	w.showHeader(idx)
+}
+
+// showHeader selects a header and focuses its content.
+func (w *Window) showHeader(idx int) {
+	w.selectTableOfContentsHeader(idx)
+	w.setFocusMode(focusContent)
+}
+
+// setStatus displays a message in the input bar.
+func (w *Window) setStatus(status string) {
+	w.status = status
+	w.renderInputBar()
+}
+
+func (w *Window) isValidSectionIndex(idx int) bool {
+	return idx >= 0 && idx < len(w.blocks)
 }
@@ -1,10 +1,17 @@
 package console
 
 import (
+	"context"
+	"errors"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/KyleBanks/kurz/pkg/doc"
+	"github.com/KyleBanks/kurz/pkg/doc/resolver"
+
+	"github.com/gdamore/tcell"
 )
 
 func TestNewWindow(t *testing.T) {
@@ -60,6 +67,40 @@ func TestWindow_RenderDocument(t *testing.T) {
 	}
 }
 
+func TestWindow_RenderDocument_singlePage(t *testing.T) {
+	w := NewWindow()
+	d := doc.Document{
+		Headers: []doc.Header{
+			{Title: "README.md", Preamble: true, Content: []doc.Section{
+				{Spans: []doc.Span{{Text: "Text"}}},
+			}},
+		},
+	}
+
+	w.RenderDocument(d)
+	if !w.singlePage {
+		t.Error("Expected singlePage for a document without headers")
+	}
+
+	if w.focusMode != focusContent {
+		t.Errorf("Unexpected w.focusMode, expected=%v, got=%v", focusContent, w.focusMode)
+	}
+
+	if got := w.contentBody.GetRegionText("0"); got != "Text\n\n" {
+		t.Errorf("Unexpected content, expected=Text, got=%v", got)
+	}
+
+	// Rendering a document with headers restores the table of contents
+	d.Headers = append(d.Headers, doc.Header{Title: "Header 1"})
+	w.RenderDocument(d)
+	if w.singlePage {
+		t.Error("Unexpected singlePage for a document with headers")
+	}
+	if w.focusMode != focusTableOfContents {
+		t.Errorf("Unexpected w.focusMode, expected=%v, got=%v", focusTableOfContents, w.focusMode)
+	}
+}
+
 func TestWindow_getSelectedHeader(t *testing.T) {
 	var w Window
 	w.doc = doc.Document{
@@ -87,12 +128,12 @@ func TestWindow_setSelectedHeader(t *testing.T) {
 	w.doc = doc.Document{
 		Headers: []doc.Header{
 			{Title: "Header 1", Content: []doc.Section{
-				{Text: "H1T1"},
-				{Text: "H1T2"},
+				{Spans: []doc.Span{{Text: "H1T1"}}},
+				{Spans: []doc.Span{{Text: "H1T2"}}},
 			}},
 			{Title: "Header 2", Content: []doc.Section{
-				{Text: "H2T1"},
-				{Text: "H2T2"},
+				{Spans: []doc.Span{{Text: "H2T1"}}},
+				{Spans: []doc.Span{{Text: "H2T2"}}},
 			}},
 		},
 	}
@@ -102,10 +143,10 @@ func TestWindow_setSelectedHeader(t *testing.T) {
 		region string
 		expect string
 	}{
-		{header: 0, region: "0", expect: "H1T1"},
-		{header: 0, region: "1", expect: "H1T2"},
-		{header: 1, region: "0", expect: "H2T1"},
-		{header: 1, region: "1", expect: "H2T2"},
+		{header: 0, region: "0", expect: "H1T1\n\n"},
+		{header: 0, region: "1", expect: "H1T2\n\n"},
+		{header: 1, region: "0", expect: "H2T1\n\n"},
+		{header: 1, region: "1", expect: "H2T2\n\n"},
 	}
 
 	for idx, tt := range tests {
@@ -133,3 +174,371 @@ func TestWindow_setSelectedHeader(t *testing.T) {
 		t.Errorf("Unexpected selectedHeader for low input, expected=1, got=%v", w.selectedHeader)
 	}
 }
+
+var linkDoc = doc.Document{
+	Source: "/docs/README.md",
+	Headers: []doc.Header{
+		{Title: "Intro", Anchor: "intro", Content: []doc.Section{
+			{Spans: []doc.Span{
+				{Text: "See "},
+				{Style: doc.Link, Link: "#usage", Children: []doc.Span{{Text: "usage"}}},
+				{Text: " and "},
+				{Style: doc.Link, Link: "guide.md#setup", Children: []doc.Span{{Text: "the guide"}}},
+			}},
+		}},
+		{Title: "Usage", Anchor: "usage"},
+	},
+}
+
+func TestWindow_selectLink(t *testing.T) {
+	w := NewWindow()
+	w.RenderDocument(linkDoc)
+	w.setFocusMode(focusContent)
+
+	tests := []struct {
+		delta  int
+		expect int
+	}{
+		{1, 0},
+		{1, 1},
+		{1, 0},
+		{-1, 1},
+	}
+
+	for idx, tt := range tests {
+		w.selectLink(tt.delta)
+		if w.selectedLink != tt.expect {
+			t.Errorf("[%d] Unexpected selectedLink, expected=%v, got=%v", idx, tt.expect, w.selectedLink)
+		}
+	}
+
+	if got := w.contentBody.GetRegionText(linkRegion(0, 1)); got != "the guide <guide.md#setup>\n\n" {
+		t.Errorf("Unexpected link region text, got=%q", got)
+	}
+
+	// Moving between sections clears the selection
+	w.setSelectedSection(0)
+	if w.selectedLink != -1 {
+		t.Errorf("Unexpected selectedLink, expected=-1, got=%v", w.selectedLink)
+	}
+}
+
+func TestWindow_followLink(t *testing.T) {
+	guide := doc.Document{
+		Source: "/docs/guide.md",
+		Headers: []doc.Header{
+			{Title: "Guide", Anchor: "guide"},
+			{Title: "Setup", Anchor: "setup"},
+		},
+	}
+
+	var loaded string
+	w := NewWindow()
+	next := queueUpdates(w)
+	w.SetLoader(func(ctx context.Context, path string) (doc.Document, error) {
+		loaded = path
+		return guide, nil
+	})
+	w.RenderDocument(linkDoc)
+
+	// Anchors within the document select the header
+	w.followLink("#usage")
+	if w.selectedHeader != 1 {
+		t.Errorf("Unexpected selectedHeader, expected=1, got=%v", w.selectedHeader)
+	}
+
+	// Other documents are resolved relative to the current one
+	w.Open(resolver.Join(w.doc.Source, "guide.md"), "setup")
+	next()
+	if loaded != "/docs/guide.md" {
+		t.Errorf("Unexpected loaded path, expected=/docs/guide.md, got=%v", loaded)
+	}
+	if w.doc.Source != guide.Source || w.selectedHeader != 1 {
+		t.Errorf("Unexpected document, expected=%v at header 1, got=%v at header %v", guide.Source, w.doc.Source, w.selectedHeader)
+	}
+
+	// Navigating back and forward through the history
+	tests := []struct {
+		delta  int
+		source string
+		header int
+	}{
+		{-1, linkDoc.Source, 1},
+		{-1, linkDoc.Source, 0},
+		{1, linkDoc.Source, 1},
+		{1, guide.Source, 1},
+	}
+
+	for idx, tt := range tests {
+		w.navigate(tt.delta)
+		if w.doc.Source != tt.source || w.selectedHeader != tt.header {
+			t.Errorf("[%d] Unexpected location, expected=%v at header %v, got=%v at header %v", idx, tt.source, tt.header, w.doc.Source, w.selectedHeader)
+		}
+	}
+
+	// Failing to load a document leaves the current one displayed
+	w.SetLoader(func(ctx context.Context, path string) (doc.Document, error) {
+		return doc.Document{}, errors.New("not found")
+	})
+	w.Open("/docs/missing.md", "")
+	next()
+	if w.doc.Source != guide.Source {
+		t.Errorf("Unexpected document after failed load, expected=%v, got=%v", guide.Source, w.doc.Source)
+	}
+	if w.inputHandler.focus != focusModal {
+		t.Error("Expected an error modal after failed load")
+	}
+}
+
+func TestWindow_setWidth(t *testing.T) {
+	w := NewWindow()
+	w.RenderDocument(doc.Document{
+		Headers: []doc.Header{
+			{Title: "Header 1", Content: []doc.Section{
+				{Kind: doc.TableSection, Table: &doc.Table{
+					Rows: []doc.TableRow{
+						{Cells: [][]doc.Span{{{Text: "first cell"}}, {{Text: "second"}}}},
+					},
+				}},
+			}},
+		},
+	})
+
+	if got := w.contentBody.GetRegionText("0"); got != "first cell │ second\n\n" {
+		t.Errorf("Unexpected content, got=%q", got)
+	}
+
+	w.setWidth(15)
+	if got := w.contentBody.GetRegionText("0"); got != "first  │ second\ncell   │\n\n" {
+		t.Errorf("Unexpected content after resize, got=%q", got)
+	}
+}
+
+func TestWindow_SelectHeader(t *testing.T) {
+	w := NewWindow()
+	w.RenderDocument(doc.Document{
+		Headers: []doc.Header{
+			{Title: "Header 1", Anchor: "header-1"},
+			{Title: "Header 2", Anchor: "header-2"},
+		},
+	})
+
+	tests := []struct {
+		name   string
+		expect int
+	}{
+		{"header-2", 1},
+		{"Header 1", 0},
+		{"missing", 0},
+	}
+
+	for idx, tt := range tests {
+		w.SelectHeader(tt.name)
+		if w.selectedHeader != tt.expect {
+			t.Errorf("[%d] Unexpected selectedHeader, expected=%v, got=%v", idx, tt.expect, w.selectedHeader)
+		}
+	}
+
+	if w.status == "" {
+		t.Error("Expected a status for a missing header")
+	}
+
+	w.SelectHeader("[red]")
+	if got := w.inputBar.GetText(false); !strings.Contains(got, "No header found for '[red[]'") {
+		t.Errorf("Unexpected input bar, got=%q", got)
+	}
+}
+
+func TestWindow_ReloadDocument(t *testing.T) {
+	d := doc.Document{
+		Source: "README.md",
+		Headers: []doc.Header{
+			{Title: "Install", Content: []doc.Section{
+				{Spans: []doc.Span{{Text: "go get"}}},
+			}},
+			{Title: "Usage", Content: []doc.Section{
+				{Spans: []doc.Span{{Text: "First"}}},
+				{Spans: []doc.Span{{Text: "Second"}}},
+			}},
+		},
+	}
+
+	w := NewWindow()
+	w.RenderDocument(d)
+	w.tableOfContents.SetCurrentItem(1)
+	w.setFocusMode(focusContent)
+	w.setSelectedSection(1)
+	w.collapseSection(0)
+
+	// Insert a header before the selected one
+	updated := d
+	updated.Headers = append([]doc.Header{{Title: "Intro"}}, d.Headers...)
+	w.ReloadDocument(updated)
+
+	if w.selectedHeader != 2 {
+		t.Errorf("Unexpected selectedHeader, expected=2, got=%v", w.selectedHeader)
+	}
+	if w.selectedSection != 1 {
+		t.Errorf("Unexpected selectedSection, expected=1, got=%v", w.selectedSection)
+	}
+	if w.focusMode != focusContent {
+		t.Errorf("Unexpected focusMode, expected=%v, got=%v", focusContent, w.focusMode)
+	}
+	if state := w.contentState.get(2, 0); state != collapsedContent {
+		t.Errorf("Unexpected content state, expected=%v, got=%v", collapsedContent, state)
+	}
+	if len(w.history.entries) != 1 {
+		t.Errorf("Unexpected history length, expected=1, got=%v", len(w.history.entries))
+	}
+
+	// Other documents are ignored
+	other := doc.Document{Source: "OTHER.md", Headers: []doc.Header{{Title: "Other"}}}
+	w.ReloadDocument(other)
+	if w.doc.Source != "README.md" {
+		t.Errorf("Unexpected document, expected=README.md, got=%v", w.doc.Source)
+	}
+}
+
+func TestWindow_ReloadDocument_overlay(t *testing.T) {
+	d := doc.Document{Source: "README.md", Headers: []doc.Header{{Title: "Install"}}}
+	updated := doc.Document{Source: "README.md", Headers: []doc.Header{{Title: "Intro"}, {Title: "Install"}}}
+
+	tests := []struct {
+		open  func(w *Window)
+		close func(w *Window)
+		focus focusMode
+	}{
+		{
+			func(w *Window) { w.showHelp() },
+			func(w *Window) { w.hideHelp() },
+			focusHelp,
+		},
+		{
+			func(w *Window) { w.startSearch() },
+			func(w *Window) { w.searchDoneHandler(tcell.KeyEscape) },
+			focusSearch,
+		},
+	}
+
+	for idx, tt := range tests {
+		w := NewWindow()
+		w.RenderDocument(d)
+		tt.open(w)
+
+		w.ReloadDocument(updated)
+		if w.inputHandler.focus != tt.focus {
+			t.Errorf("[%d] Unexpected input focus, expected=%v, got=%v", idx, tt.focus, w.inputHandler.focus)
+		}
+		if len(w.doc.Headers) != 1 {
+			t.Errorf("[%d] Expected the reload to wait for the overlay to close, got=%v headers", idx, len(w.doc.Headers))
+		}
+
+		tt.close(w)
+		if len(w.doc.Headers) != 2 {
+			t.Errorf("[%d] Expected the reload once the overlay closed, got=%v headers", idx, len(w.doc.Headers))
+		}
+		if w.inputHandler.focus != focusTableOfContents {
+			t.Errorf("[%d] Unexpected input focus, expected=%v, got=%v", idx, focusTableOfContents, w.inputHandler.focus)
+		}
+	}
+}
+
+func TestMatchHeaders(t *testing.T) {
+	from := doc.Document{Headers: []doc.Header{{Title: "A"}, {Title: "B"}, {Title: "B"}, {Title: "C"}}}
+	to := doc.Document{Headers: []doc.Header{{Title: "B"}, {Title: "A"}, {Title: "B"}}}
+
+	expect := map[int]int{0: 1, 1: 0, 2: 2}
+	if got := matchHeaders(from, to); !reflect.DeepEqual(got, expect) {
+		t.Errorf("Unexpected headers, expected=%v, got=%v", expect, got)
+	}
+}
+
+func TestWindow_Open_cancel(t *testing.T) {
+	w := NewWindow()
+	next := queueUpdates(w)
+	w.RenderDocument(doc.Document{Source: "README.md", Headers: []doc.Header{{Title: "Header"}}})
+
+	w.SetLoader(func(ctx context.Context, path string) (doc.Document, error) {
+		doc.Progress(ctx, "Trying %v", path)
+		<-ctx.Done()
+		return doc.Document{}, ctx.Err()
+	})
+
+	w.Open("guide.md", "")
+	if w.inputHandler.focus != focusLoading {
+		t.Errorf("Unexpected input focus, expected=%v, got=%v", focusLoading, w.inputHandler.focus)
+	}
+
+	// Display the progress, then cancel. The cancelled document's result
+	// is ignored.
+	next()
+	w.inputHandler.handle(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	next()
+
+	if w.inputHandler.focus != focusTableOfContents {
+		t.Errorf("Unexpected input focus after cancel, expected=%v, got=%v", focusTableOfContents, w.inputHandler.focus)
+	}
+	if w.doc.Source != "README.md" {
+		t.Errorf("Unexpected document after cancel, expected=README.md, got=%v", w.doc.Source)
+	}
+}
+
+func TestWindow_Open_cancelFirst(t *testing.T) {
+	w := NewWindow()
+	next := queueUpdates(w)
+	w.SetLoader(func(ctx context.Context, path string) (doc.Document, error) {
+		<-ctx.Done()
+		return doc.Document{}, ctx.Err()
+	})
+
+	// Without a previous view to return to, cancelling exits rather than
+	// displaying the cancellation as an error.
+	w.Open("guide.md", "")
+	w.inputHandler.handle(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	next()
+
+	if w.inputHandler.focus == focusModal {
+		t.Error("Unexpected error modal after cancelling the first document")
+	}
+}
+
+func TestWindow_Open_timeout(t *testing.T) {
+	w := NewWindow()
+	w.SetTimeout(time.Millisecond)
+
+	errs := make(chan error, 1)
+	w.SetLoader(func(ctx context.Context, path string) (doc.Document, error) {
+		<-ctx.Done()
+		errs <- ctx.Err()
+		return doc.Document{}, ctx.Err()
+	})
+
+	next := queueUpdates(w)
+	w.Open("guide.md", "")
+	if err := <-errs; err != context.DeadlineExceeded {
+		t.Errorf("Unexpected error, expected=%v, got=%v", context.DeadlineExceeded, err)
+	}
+
+	next()
+	if w.inputHandler.focus != focusModal {
+		t.Error("Expected an error modal after timing out")
+	}
+}
+
+// queueUpdates replaces the window's updates with a queue that's run on
+// the test's goroutine, returning a function that runs the next update.
+func queueUpdates(w *Window) func() {
+	updates := make(chan func())
+	w.update = func(f func()) {
+		done := make(chan struct{})
+		updates <- func() {
+			f()
+			close(done)
+		}
+		<-done
+	}
+
+	return func() {
+		(<-updates)()
+	}
+}
@@ -7,15 +7,18 @@ const (
 )
 
 // contentState maps a heading + section index to a
-// special state string.
+// special state string, and tracks the headings that are folded
+// in the table of contents.
 type contentState struct {
-	store map[string]string
+	store  map[string]string
+	folded map[int]bool
 }
 
 // newContentState initializes and returns a new contentState type.
 func newContentState() *contentState {
 	return &contentState{
-		store: make(map[string]string),
+		store:  make(map[string]string),
+		folded: make(map[int]bool),
 	}
 }
 
@@ -39,6 +42,29 @@ func (c *contentState) clear(heading, section int) {
 	delete(c.store, contentKey(heading, section))
 }
 
+// remap returns a copy of the content state with each heading index
+// replaced according to the mapping. States of headings that are not
+// in the mapping are discarded.
+func (c *contentState) remap(headings map[int]int) *contentState {
+	r := newContentState()
+	for k, s := range c.store {
+		var heading, section int
+		if _, err := fmt.Sscanf(k, "%d:%d", &heading, &section); err != nil {
+			continue
+		}
+
+		if h, ok := headings[heading]; ok {
+			r.set(h, section, s)
+		}
+	}
+	for heading := range c.folded {
+		if h, ok := headings[heading]; ok {
+			r.folded[h] = true
+		}
+	}
+	return r
+}
+
 // contentKey returns a unique key for any heading+section combination.
 func contentKey(heading int, section int) string {
 	return fmt.Sprintf("%d:%d", heading, section)
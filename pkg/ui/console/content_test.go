@@ -1,6 +1,9 @@
 package console
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestContentState_get(t *testing.T) {
 	c := newContentState()
@@ -71,3 +74,22 @@ func TestContentKey(t *testing.T) {
 		t.Fatalf("Unexpected contentKey, expected=1:4, got=%v", k)
 	}
 }
+
+func TestContentState_remap(t *testing.T) {
+	c := newContentState()
+	c.set(0, 1, "ZERO")
+	c.set(1, 2, "ONE")
+	c.set(2, 0, "TWO")
+	c.folded[1] = true
+	c.folded[2] = true
+
+	r := c.remap(map[int]int{0: 0, 1: 3})
+
+	expect := map[string]string{"0:1": "ZERO", "3:2": "ONE"}
+	if !reflect.DeepEqual(r.store, expect) {
+		t.Errorf("Unexpected store, expected=%v, got=%v", expect, r.store)
+	}
+	if expect := map[int]bool{3: true}; !reflect.DeepEqual(r.folded, expect) {
+		t.Errorf("Unexpected folded, expected=%v, got=%v", expect, r.folded)
+	}
+}
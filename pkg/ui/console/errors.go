@@ -0,0 +1,78 @@
+package console
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+	"github.com/KyleBanks/kurz/pkg/doc/resolver"
+
+	"github.com/rivo/tview"
+)
+
+// Labels of the buttons of the error modal.
+const (
+	retryButton = "Retry"
+	closeButton = "Close"
+	quitButton  = "Quit"
+)
+
+// ShowError displays the error in a modal describing its category, with
+// buttons to retry the failed action if a retry function is provided, close
+// the modal if there is a document or file navigator to return to, or quit.
+// The retry function is called on the UI goroutine.
+func (w *Window) ShowError(err error, retry func()) {
+	var buttons []string
+	if retry != nil {
+		buttons = append(buttons, retryButton)
+	}
+	canClose := w.source.Headers != nil || w.files != nil
+	if canClose {
+		buttons = append(buttons, closeButton)
+	}
+	buttons = append(buttons, quitButton)
+
+	m := tview.NewModal().
+		SetText(fmt.Sprintf("%v\n\n%v", errorTitle(err), err)).
+		AddButtons(buttons).
+		SetDoneFunc(func(_ int, label string) {
+			switch {
+			case label == retryButton:
+				w.ShowMessage("Retrying...")
+				retry()
+			case label == closeButton || (label == "" && canClose):
+				w.HideMessage()
+				w.restoreFocus()
+			default:
+				w.Stop()
+			}
+		})
+
+	w.SetRoot(m, true)
+	w.inputHandler.setFocusMode(focusModal)
+}
+
+// errorTitle describes the category of an error, distinguishing documents
+// that could not be found from network and parsing failures.
+func errorTitle(err error) string {
+	var parseErr *doc.ParseError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &parseErr):
+		return "Unable to parse the document"
+	case errors.Is(err, resolver.ErrInvalidPath), errors.Is(err, os.ErrNotExist):
+		return "Document not found"
+	case errors.Is(err, resolver.ErrAuthRequired):
+		return "Authentication required"
+	case errors.Is(err, resolver.ErrForbidden):
+		return "Access forbidden"
+	case errors.Is(err, resolver.ErrNotCached):
+		return "Document not available offline"
+	case errors.As(err, &netErr):
+		return "Network error"
+	default:
+		return "Error"
+	}
+}
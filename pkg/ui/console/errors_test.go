@@ -0,0 +1,56 @@
+package console
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+	"github.com/KyleBanks/kurz/pkg/doc/resolver"
+)
+
+func TestErrorTitle(t *testing.T) {
+	tests := []struct {
+		err    error
+		expect string
+	}{
+		{resolver.ErrInvalidPath, "Document not found"},
+		{fmt.Errorf("wrapped: %w", os.ErrNotExist), "Document not found"},
+		{resolver.ErrNotCached, "Document not available offline"},
+		{&resolver.AuthError{Host: "github.com"}, "Authentication required"},
+		{&resolver.AuthError{Host: "github.com", Status: 403}, "Access forbidden"},
+		{&resolver.AuthError{Host: "github.com", Status: 404}, "Document not found"},
+		{&net.OpError{Op: "dial", Err: errors.New("connection refused")}, "Network error"},
+		{&doc.ParseError{Path: "README.md", Err: errors.New("bad")}, "Unable to parse the document"},
+		{errors.New("other"), "Error"},
+	}
+
+	for idx, tt := range tests {
+		if got := errorTitle(tt.err); got != tt.expect {
+			t.Errorf("[%d] Unexpected title, expected=%v, got=%v", idx, tt.expect, got)
+		}
+	}
+}
+
+func TestWindow_ShowError(t *testing.T) {
+	w := NewWindow()
+	w.ShowError(resolver.ErrInvalidPath, nil)
+	if w.inputHandler.focus != focusModal {
+		t.Errorf("Unexpected input focus, expected=%v, got=%v", focusModal, w.inputHandler.focus)
+	}
+
+	// Inputs are passed to the modal's buttons
+	w.RenderDocument(doc.Document{Headers: []doc.Header{{Title: "Header"}}})
+	w.ShowError(resolver.ErrInvalidPath, nil)
+	if len(w.inputHandler.inputs()) != 0 {
+		t.Errorf("Unexpected inputs for modal, got=%v", w.inputHandler.inputs())
+	}
+
+	w.HideMessage()
+	w.restoreFocus()
+	if w.inputHandler.focus != focusTableOfContents {
+		t.Errorf("Unexpected input focus, expected=%v, got=%v", focusTableOfContents, w.inputHandler.focus)
+	}
+}
@@ -0,0 +1,96 @@
+package console
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+
+	"github.com/rivo/tview"
+)
+
+// RenderFiles displays a navigator listing the markdown files within a
+// directory, provided as slash separated paths relative to it. Selecting
+// a file opens it using the Window's Loader.
+func (w *Window) RenderFiles(dir string, files []string) {
+	w.HideMessage()
+
+	var items []string
+	w.dir = dir
+	items, w.files = fileTree(files)
+
+	w.FileList().Clear()
+	for _, item := range items {
+		w.fileList.AddItem(item, "", 0, nil)
+	}
+
+	w.inputHandler.setInputs()
+	w.showFiles()
+}
+
+// showFiles replaces the table of contents and content with the
+// file navigator.
+func (w *Window) showFiles() {
+	if w.files == nil {
+		return
+	}
+
+	w.layout.Clear()
+	w.layout.AddItem(w.FileList(), 0, 0, 1, 4, 0, 0, false)
+	w.setFocusMode(focusFiles)
+}
+
+func (w *Window) FileList() *tview.List {
+	if w.fileList == nil {
+		w.fileList = tview.NewList().
+			ShowSecondaryText(false)
+	}
+
+	return w.fileList
+}
+
+// openFile loads the file at the index of the navigator, ignoring
+// directories.
+func (w *Window) openFile(idx int) {
+	if idx < 0 || idx >= len(w.files) || w.files[idx] == "" {
+		return
+	}
+
+	w.Open(filepath.Join(w.dir, filepath.FromSlash(w.files[idx])), "")
+}
+
+// fileTree returns the items displayed in the navigator for the sorted,
+// slash separated file paths, along with the path of the file that each
+// item represents. Files are listed beneath an entry for each of their
+// directories, which have an empty path.
+func fileTree(files []string) (items []string, paths []string) {
+	var prev []string
+	for _, f := range files {
+		var dirs []string
+		if dir := path.Dir(f); dir != "." {
+			dirs = strings.Split(dir, "/")
+		}
+
+		// Skip the directories shared with the previous file.
+		var shared int
+		for shared < len(dirs) && shared < len(prev) && dirs[shared] == prev[shared] {
+			shared++
+		}
+
+		for i := shared; i < len(dirs); i++ {
+			items = append(items, treeIndent(i)+Styler{}.Style(dirs[i]+"/", doc.Bold))
+			paths = append(paths, "")
+		}
+
+		items = append(items, treeIndent(len(dirs))+tview.Escape(path.Base(f)))
+		paths = append(paths, f)
+		prev = dirs
+	}
+	return items, paths
+}
+
+// treeIndent returns the indentation of an item at the depth of the tree.
+func treeIndent(depth int) string {
+	return strings.Repeat("  ", depth)
+}
@@ -0,0 +1,85 @@
+package console
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+)
+
+var testFiles = []string{
+	"README.md",
+	"docs/api/public.md",
+	"docs/guide.md",
+	"examples/basic.md",
+}
+
+func TestFileTree(t *testing.T) {
+	items, paths := fileTree(testFiles)
+
+	expectItems := []string{
+		"README.md",
+		"docs/",
+		"  api/",
+		"    public.md",
+		"  guide.md",
+		"examples/",
+		"  basic.md",
+	}
+	var got []string
+	for _, item := range items {
+		// Remove the styling applied to directories.
+		item = strings.Replace(item, "[::b]", "", 1)
+		item = strings.Replace(item, "[-:-:-]", "", 1)
+		got = append(got, item)
+	}
+	if !reflect.DeepEqual(got, expectItems) {
+		t.Errorf("Unexpected items, expected=%q, got=%q", expectItems, got)
+	}
+
+	expectPaths := []string{"README.md", "", "", "docs/api/public.md", "docs/guide.md", "", "examples/basic.md"}
+	if !reflect.DeepEqual(paths, expectPaths) {
+		t.Errorf("Unexpected paths, expected=%q, got=%q", expectPaths, paths)
+	}
+}
+
+func TestWindow_RenderFiles(t *testing.T) {
+	var loaded string
+	w := NewWindow()
+	next := queueUpdates(w)
+	w.SetLoader(func(ctx context.Context, path string) (doc.Document, error) {
+		loaded = path
+		return doc.Document{Source: path, Headers: []doc.Header{{Title: "Guide"}}}, nil
+	})
+
+	w.RenderFiles("/repo", testFiles)
+	if w.focusMode != focusFiles {
+		t.Errorf("Unexpected focusMode, expected=%v, got=%v", focusFiles, w.focusMode)
+	}
+	if w.fileList.GetItemCount() != 7 {
+		t.Errorf("Unexpected item count, expected=7, got=%v", w.fileList.GetItemCount())
+	}
+
+	// Directories can't be opened
+	w.openFile(1)
+	if loaded != "" {
+		t.Errorf("Unexpected load of directory, got=%v", loaded)
+	}
+
+	w.openFile(4)
+	next()
+	if loaded != "/repo/docs/guide.md" {
+		t.Errorf("Unexpected loaded path, expected=/repo/docs/guide.md, got=%v", loaded)
+	}
+	if w.focusMode != focusTableOfContents {
+		t.Errorf("Unexpected focusMode after load, expected=%v, got=%v", focusTableOfContents, w.focusMode)
+	}
+
+	// Returning to the navigator
+	w.showFiles()
+	if w.focusMode != focusFiles {
+		t.Errorf("Unexpected focusMode after return, expected=%v, got=%v", focusFiles, w.focusMode)
+	}
+}
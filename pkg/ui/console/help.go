@@ -0,0 +1,122 @@
+package console
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+const (
+	// helpWidth is the width of the help overlay.
+	helpWidth = 64
+
+	// mainPage and helpPage name the pages of the window.
+	mainPage = "main"
+	helpPage = "help"
+)
+
+// HelpView returns the scrollable view listing the key bindings.
+func (w *Window) HelpView() *tview.TextView {
+	if w.helpView == nil {
+		w.helpView = tview.NewTextView().
+			SetDynamicColors(true).
+			SetScrollable(true).
+			SetWrap(false)
+		w.helpView.
+			SetBorder(true).
+			SetTitle(" Help ")
+	}
+
+	return w.helpView
+}
+
+// showHelp displays the help overlay as a page above the layout, leaving
+// the input bar visible below it.
+func (w *Window) showHelp() {
+	w.HelpView().
+		SetText(w.inputHandler.helpText()).
+		ScrollToBeginning()
+
+	overlay := tview.NewGrid().
+		SetColumns(0, helpWidth, 0).
+		SetRows(1, 0, 1).
+		AddItem(w.helpView, 1, 1, 1, 1, 0, 0, true)
+
+	w.pages.AddPage(helpPage, overlay, true, true)
+	w.SetFocus(w.helpView)
+	w.inputHandler.setFocusMode(focusHelp)
+	w.renderInputBar()
+}
+
+// hideHelp closes the help overlay, restoring focus to the primitive
+// that was focused when it was opened.
+func (w *Window) hideHelp() {
+	w.pages.RemovePage(helpPage)
+	w.restoreFocus()
+}
+
+// restoreFocus focuses the primitive of the current focus mode after an
+// overlay has been closed, without changing its selection.
+func (w *Window) restoreFocus() {
+	switch w.focusMode {
+	case focusTableOfContents:
+		w.SetFocus(w.tableOfContents)
+	case focusContent:
+		w.SetFocus(w.contentBody)
+	case focusFiles:
+		w.SetFocus(w.fileList)
+	}
+	w.inputHandler.setFocusMode(w.focusMode)
+	w.renderInputBar()
+	w.applyReload()
+}
+
+// helpText lists the keys and description of every input in each focus
+// mode, ignoring those that don't do anything.
+func (i *inputHandler) helpText() string {
+	groups := []struct {
+		title  string
+		inputs []input
+	}{
+		{"Table of Contents", i.tableOfContents},
+		{"Content", i.content},
+	}
+	if i.w.files != nil {
+		groups = append(groups, struct {
+			title  string
+			inputs []input
+		}{"Files", i.files})
+	}
+	groups = append(groups, struct {
+		title  string
+		inputs []input
+	}{"Help", i.help})
+
+	var buf bytes.Buffer
+	for idx, g := range groups {
+		if idx > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "[::b]%v[-:-:-]\n", g.title)
+
+		for _, in := range g.inputs {
+			if in.fn == nil && in.countFn == nil {
+				continue
+			}
+			description := actionDescriptions[in.action]
+			if in.action == "" {
+				description = in.label
+			}
+
+			keys := strings.Join(in.keyNames(), ", ")
+			pad := 20 - len([]rune(keys))
+			if pad < 1 {
+				pad = 1
+			}
+			fmt.Fprintf(&buf, "  %v%v%v\n", tview.Escape(keys), strings.Repeat(" ", pad), description)
+		}
+	}
+	return buf.String()
+}
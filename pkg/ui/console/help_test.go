@@ -0,0 +1,82 @@
+package console
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestActionDescriptions(t *testing.T) {
+	for _, a := range Actions {
+		if actionDescriptions[a] == "" {
+			t.Errorf("Missing description for action %v", a)
+		}
+	}
+}
+
+func TestWindow_showHelp(t *testing.T) {
+	w := NewWindow()
+	w.RenderDocument(doc.Document{
+		Headers: []doc.Header{
+			{Title: "Header 1"},
+			{Title: "Header 2"},
+		},
+	})
+	w.setFocusMode(focusContent)
+
+	w.inputHandler.handle(tcell.NewEventKey(tcell.KeyRune, '?', tcell.ModNone))
+	if w.inputHandler.focus != focusHelp {
+		t.Fatalf("Unexpected input focus, expected=%v, got=%v", focusHelp, w.inputHandler.focus)
+	}
+	if !w.pages.HasPage(helpPage) {
+		t.Error("Expected the help page to be shown")
+	}
+
+	text := w.inputHandler.helpText()
+	for _, expect := range []string{
+		"Table of Contents",
+		"Content",
+		"Down                Move down",
+		"Tab                 Select the next link",
+		"?                   Show or hide this help",
+		"Esc, ?              Show or hide this help",
+	} {
+		if !strings.Contains(text, expect) {
+			t.Errorf("Expected help to contain %q, got=%v", expect, text)
+		}
+	}
+	if strings.Contains(text, "Files") {
+		t.Errorf("Unexpected file inputs in help, got=%v", text)
+	}
+
+	w.inputHandler.handle(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	if w.pages.HasPage(helpPage) {
+		t.Error("Expected the help page to be removed")
+	}
+	if w.inputHandler.focus != focusContent {
+		t.Errorf("Unexpected input focus, expected=%v, got=%v", focusContent, w.inputHandler.focus)
+	}
+	if w.focusMode != focusContent {
+		t.Errorf("Unexpected focusMode, expected=%v, got=%v", focusContent, w.focusMode)
+	}
+}
+
+func TestInputHandler_helpText(t *testing.T) {
+	w := NewWindow()
+	if err := w.SetKeyPreset("emacs"); err != nil {
+		t.Fatal(err)
+	}
+
+	text := w.inputHandler.helpText()
+	for _, expect := range []string{
+		"Home, M-<",
+		"Esc, Ctrl-X Ctrl-C",
+	} {
+		if !strings.Contains(text, expect) {
+			t.Errorf("Expected help to contain %q, got=%v", expect, text)
+		}
+	}
+}
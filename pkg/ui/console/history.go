@@ -0,0 +1,54 @@
+package console
+
+import (
+	"github.com/KyleBanks/kurz/pkg/doc"
+)
+
+// historyEntry is a document that has been displayed in the Window,
+// along with the state it was in when it was navigated away from.
+type historyEntry struct {
+	doc    doc.Document
+	header int
+	state  *contentState
+}
+
+// history is the list of documents that have been navigated through,
+// allowing the Window to move back and forward between them.
+type history struct {
+	entries []historyEntry
+	current int
+}
+
+func newHistory() *history {
+	return &history{
+		current: -1,
+	}
+}
+
+// push adds an entry after the current one, discarding any entries
+// that could previously be navigated forward to.
+func (h *history) push(e historyEntry) {
+	h.entries = append(h.entries[:h.current+1], e)
+	h.current++
+}
+
+// move moves back (if the delta is negative) or forward through the
+// history, returning false if there is no entry to move to.
+func (h *history) move(delta int) (historyEntry, bool) {
+	next := h.current + delta
+	if next < 0 || next >= len(h.entries) {
+		return historyEntry{}, false
+	}
+
+	h.current = next
+	return h.entries[next], true
+}
+
+// setHeader records the selected header of the current entry.
+func (h *history) setHeader(header int) {
+	if h.current < 0 {
+		return
+	}
+
+	h.entries[h.current].header = header
+}
@@ -0,0 +1,57 @@
+package console
+
+import (
+	"testing"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+)
+
+func TestHistory(t *testing.T) {
+	h := newHistory()
+	if _, ok := h.move(-1); ok {
+		t.Error("Unexpected entry in empty history")
+	}
+
+	docs := []doc.Document{{Title: "one"}, {Title: "two"}, {Title: "three"}}
+	for _, d := range docs {
+		h.push(historyEntry{doc: d})
+	}
+	h.setHeader(2)
+
+	tests := []struct {
+		delta  int
+		expect string
+		ok     bool
+	}{
+		{-1, "two", true},
+		{-1, "one", true},
+		{-1, "", false},
+		{1, "two", true},
+		{1, "three", true},
+		{1, "", false},
+	}
+
+	for idx, tt := range tests {
+		e, ok := h.move(tt.delta)
+		if ok != tt.ok {
+			t.Fatalf("[%d] Unexpected ok, expected=%v, got=%v", idx, tt.ok, ok)
+		}
+		if e.doc.Title != tt.expect {
+			t.Errorf("[%d] Unexpected entry, expected=%v, got=%v", idx, tt.expect, e.doc.Title)
+		}
+	}
+
+	if h.entries[2].header != 2 {
+		t.Errorf("Unexpected header, expected=2, got=%v", h.entries[2].header)
+	}
+
+	// Pushing discards the forward entries
+	h.move(-1)
+	h.push(historyEntry{doc: doc.Document{Title: "four"}})
+	if len(h.entries) != 3 || h.entries[2].doc.Title != "four" {
+		t.Errorf("Unexpected entries after push, got=%v", h.entries)
+	}
+	if _, ok := h.move(1); ok {
+		t.Error("Unexpected forward entry after push")
+	}
+}
@@ -5,16 +5,37 @@ import (
 	"fmt"
 
 	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
 )
 
+// pageSize is the number of items or sections moved by a page.
+const pageSize = 5
+
 type input struct {
+	// action names the input so that it can be bound to other keys.
+	// See Window.SetKeyBindings.
+	action string
+
 	symbol  string
 	label   string
 	fn      func()
 	swallow bool
 
+	// countFn is called in place of fn with the count typed before the
+	// input, or the digit pressed if the input is bound to digits, rather
+	// than repeating the input.
+	countFn func(n int)
+
 	keys  []tcell.Key
 	runes []rune
+
+	// sequences contains bindings of multiple keys, or keys pressed
+	// with the Alt modifier.
+	sequences [][]stroke
+
+	// forward is passed on in place of the event when an input that is
+	// handled by the focused primitive has been bound to other keys.
+	forward *tcell.EventKey
 }
 
 func (i input) String() string {
@@ -31,6 +52,14 @@ type inputHandler struct {
 
 	tableOfContents []input
 	content         []input
+	files           []input
+	help            []input
+	loading         []input
+
+	// pending contains the keys typed so far of a partially matched
+	// sequence, and count is the number typed before an input.
+	pending []stroke
+	count   int
 }
 
 func newInputHandler(w *Window) *inputHandler {
@@ -45,49 +74,105 @@ func newInputHandler(w *Window) *inputHandler {
 }
 
 func (i *inputHandler) handle(e *tcell.EventKey) *tcell.EventKey {
-	if e.Key() == tcell.KeyRune {
-		return i.handleRune(e)
-	} else {
-		return i.handleKey(e)
+	inputs := i.inputs()
+	if len(inputs) == 0 {
+		return e
+	}
+
+	s := newStroke(e)
+	if i.w.keyCounts && len(i.pending) == 0 && s.isCount(i.count) {
+		i.count = i.count*10 + int(s.r-'0')
+		return nil
 	}
-}
 
-func (i *inputHandler) handleRune(e *tcell.EventKey) *tcell.EventKey {
-	for _, input := range i.inputs() {
-		for _, r := range input.runes {
-			if r != e.Rune() {
+	seq := append(i.pending, s)
+	i.pending = nil
+	var partial bool
+	for _, input := range inputs {
+		for _, q := range input.sequences {
+			if !hasStrokes(q, seq) {
 				continue
 			}
-
-			if input.fn != nil {
-				input.fn()
+			if len(q) == len(seq) {
+				return i.run(input, e)
 			}
+			partial = true
+		}
+	}
+	if partial {
+		i.pending = seq
+		return nil
+	}
+	if len(seq) > 1 {
+		// The pending sequence didn't match, so the event is handled alone.
+		return i.handle(e)
+	}
 
-			if input.swallow {
-				return nil
-			}
-			return e
+	for _, input := range inputs {
+		if input.matches(s) {
+			return i.run(input, e)
 		}
 	}
+
+	i.count = 0
 	return e
 }
 
-func (i *inputHandler) handleKey(e *tcell.EventKey) *tcell.EventKey {
-	for _, input := range i.inputs() {
-		for _, k := range input.keys {
-			if k != e.Key() {
-				continue
-			}
+// run calls the input's function, repeated by the count if one was typed.
+func (i *inputHandler) run(in input, e *tcell.EventKey) *tcell.EventKey {
+	n := i.count
+	i.count = 0
+	if in.countFn != nil {
+		if e.Key() == tcell.KeyRune && e.Rune() >= '0' && e.Rune() <= '9' {
+			n = int(e.Rune() - '0')
+		}
+		in.countFn(n)
+		return in.result(e)
+	}
 
-			if input.fn != nil {
-				input.fn()
-			}
+	if n < 1 {
+		n = 1
+	}
+
+	if in.fn != nil {
+		for ; n > 0; n-- {
+			in.fn()
+		}
+	}
+	return in.result(e)
+}
 
-			if input.swallow {
-				return nil
+// matches returns true if the stroke is one of the input's keys or runes.
+// Runes pressed with the Alt modifier only match sequences.
+func (i input) matches(s stroke) bool {
+	if s.key == tcell.KeyRune {
+		if s.alt {
+			return false
+		}
+		for _, r := range i.runes {
+			if r == s.r {
+				return true
 			}
-			return e
 		}
+		return false
+	}
+
+	for _, k := range i.keys {
+		if k == s.key {
+			return true
+		}
+	}
+	return false
+}
+
+// result returns the event to pass on to the focused primitive after
+// the input has handled it.
+func (i input) result(e *tcell.EventKey) *tcell.EventKey {
+	if i.swallow {
+		return nil
+	}
+	if i.forward != nil {
+		return i.forward
 	}
 	return e
 }
@@ -103,6 +188,12 @@ func (i *inputHandler) inputs() []input {
 		inputs = i.tableOfContents
 	case focusContent:
 		inputs = i.content
+	case focusFiles:
+		inputs = i.files
+	case focusHelp:
+		inputs = i.help
+	case focusLoading:
+		inputs = i.loading
 	}
 	return inputs
 }
@@ -124,22 +215,17 @@ func (i *inputHandler) String() string {
 func (i *inputHandler) setInputs() {
 	i.tableOfContents = []input{
 		{
+			action: "exit",
 			symbol: " ESC ",
 			label:  "Exit",
 			keys:   []tcell.Key{tcell.KeyEscape},
 			fn:     i.w.Stop,
 		},
+	}
+	i.tableOfContents = append(i.tableOfContents, i.listInputs(i.w.TableOfContents())...)
+	i.tableOfContents = append(i.tableOfContents, []input{
 		{
-			symbol: "⬆ ",
-			label:  "Up",
-			keys:   []tcell.Key{tcell.KeyUp},
-		},
-		{
-			symbol: "⬇ ",
-			label:  "Down",
-			keys:   []tcell.Key{tcell.KeyDown},
-		},
-		{
+			action:  "select",
 			symbol:  " ➡ / ENTER ",
 			label:   "Select",
 			keys:    []tcell.Key{tcell.KeyRight, tcell.KeyEnter},
@@ -150,44 +236,337 @@ func (i *inputHandler) setInputs() {
 			keys:    []tcell.Key{tcell.KeyLeft},
 			swallow: true,
 		},
+		{
+			action:  "fold",
+			symbol:  " SPACE ",
+			label:   "Fold",
+			runes:   []rune{32}, // space
+			fn:      i.w.toggleFold,
+			swallow: true,
+		},
+		{
+			action:  "fold-level",
+			runes:   []rune{'0', '1', '2', '3', '4', '5', '6'},
+			countFn: i.w.foldToLevel,
+			swallow: true,
+		},
+	}...)
+	i.tableOfContents = append(i.tableOfContents, i.chapterInputs()...)
+	i.tableOfContents = append(i.tableOfContents, i.searchInputs()...)
+	i.tableOfContents = append(i.tableOfContents, i.historyInputs()...)
+	i.tableOfContents = append(i.tableOfContents, i.fileInputs()...)
+	i.tableOfContents = append(i.tableOfContents, i.helpInputs()...)
+
+	backLabel := "Go Back"
+	if i.w.singlePage && i.w.files == nil {
+		backLabel = "Exit"
 	}
 
 	i.content = []input{
 		{
+			action:  "leave",
 			symbol:  " ⬅ / ESC ",
-			label:   "Go Back",
+			label:   backLabel,
 			keys:    []tcell.Key{tcell.KeyLeft, tcell.KeyEscape},
-			fn:      func() { i.w.setFocusMode(focusTableOfContents) },
+			fn:      i.w.leaveContent,
 			swallow: true,
 		},
 		{
+			action: "up",
 			symbol: "⬆ ",
 			label:  "Up",
 			keys:   []tcell.Key{tcell.KeyUp},
 			fn:     func() { i.w.setSelectedSection(i.w.selectedSection - 1) },
 		},
 		{
+			action: "down",
 			symbol: "⬇ ",
 			label:  "Down",
 			keys:   []tcell.Key{tcell.KeyDown},
 			fn:     func() { i.w.setSelectedSection(i.w.selectedSection + 1) },
 		},
 		{
+			action:  "top",
+			keys:    []tcell.Key{tcell.KeyHome},
+			fn:      func() { i.w.setSelectedSection(0) },
+			swallow: true,
+		},
+		{
+			action:  "bottom",
+			keys:    []tcell.Key{tcell.KeyEnd},
+			fn:      func() { i.w.setSelectedSection(-1) },
+			swallow: true,
+		},
+		{
+			action:  "page-up",
+			keys:    []tcell.Key{tcell.KeyPgUp},
+			fn:      func() { i.w.pageSection(-pageSize) },
+			swallow: true,
+		},
+		{
+			action:  "page-down",
+			keys:    []tcell.Key{tcell.KeyPgDn},
+			fn:      func() { i.w.pageSection(pageSize) },
+			swallow: true,
+		},
+		{
+			action: "collapse",
 			symbol: " SPACE ",
 			label:  "Collapse",
 			runes:  []rune{32}, // space
 			fn:     func() { i.w.collapseSection(i.w.selectedSection) },
 		},
 		{
+			action:  "copy",
 			symbol:  " C ",
 			label:   "Copy",
 			runes:   []rune{99}, // c
 			fn:      func() { i.w.copySection(i.w.selectedSection) },
 			swallow: true,
 		},
+		{
+			action:  "next-link",
+			symbol:  " TAB ",
+			label:   "Next Link",
+			keys:    []tcell.Key{tcell.KeyTab},
+			fn:      func() { i.w.selectLink(1) },
+			swallow: true,
+		},
+		{
+			action:  "previous-link",
+			keys:    []tcell.Key{tcell.KeyBacktab},
+			fn:      func() { i.w.selectLink(-1) },
+			swallow: true,
+		},
+		{
+			action:  "open-link",
+			symbol:  " ENTER ",
+			label:   "Open Link",
+			keys:    []tcell.Key{tcell.KeyEnter},
+			fn:      i.w.openLink,
+			swallow: true,
+		},
 		{
 			keys:    []tcell.Key{tcell.KeyRight},
 			swallow: true,
 		},
 	}
+	i.content = append(i.content, i.chapterInputs()...)
+	i.content = append(i.content, i.searchInputs()...)
+	i.content = append(i.content, i.historyInputs()...)
+	i.content = append(i.content, i.fileInputs()...)
+	i.content = append(i.content, i.helpInputs()...)
+
+	i.files = []input{
+		{
+			action: "exit",
+			symbol: " ESC ",
+			label:  "Exit",
+			keys:   []tcell.Key{tcell.KeyEscape},
+			fn:     i.w.Stop,
+		},
+	}
+	i.files = append(i.files, i.listInputs(i.w.FileList())...)
+	i.files = append(i.files, input{
+		action:  "open",
+		symbol:  " ➡ / ENTER ",
+		label:   "Open",
+		keys:    []tcell.Key{tcell.KeyRight, tcell.KeyEnter},
+		fn:      func() { i.w.openFile(i.w.fileList.GetCurrentItem()) },
+		swallow: true,
+	})
+	i.files = append(i.files, i.helpInputs()...)
+
+	i.help = []input{
+		{
+			action:  "help",
+			symbol:  " ? / ESC ",
+			label:   "Close",
+			runes:   []rune{'?'},
+			keys:    []tcell.Key{tcell.KeyEscape},
+			fn:      i.w.hideHelp,
+			swallow: true,
+		},
+	}
+
+	i.loading = []input{
+		{
+			symbol:  " ESC ",
+			label:   "Cancel",
+			keys:    []tcell.Key{tcell.KeyEscape},
+			fn:      i.w.cancelLoading,
+			swallow: true,
+		},
+	}
+
+	for _, inputs := range [][]input{i.tableOfContents, i.content, i.files, i.help} {
+		for idx := range inputs {
+			if b, ok := i.w.keyBindings[inputs[idx].action]; ok {
+				inputs[idx].bind(b)
+			}
+		}
+	}
+}
+
+// listInputs returns the inputs used to move through the items of a list,
+// which wrap around at the ends of the list.
+func (i *inputHandler) listInputs(l *tview.List) []input {
+	move := func(fn func(current, count int) int) func() {
+		return func() {
+			if n := l.GetItemCount(); n > 0 {
+				l.SetCurrentItem(fn(l.GetCurrentItem(), n))
+			}
+		}
+	}
+
+	return []input{
+		{
+			action:  "up",
+			symbol:  "⬆ ",
+			label:   "Up",
+			keys:    []tcell.Key{tcell.KeyUp},
+			fn:      move(func(c, n int) int { return (c - 1 + n) % n }),
+			swallow: true,
+		},
+		{
+			action:  "down",
+			symbol:  "⬇ ",
+			label:   "Down",
+			keys:    []tcell.Key{tcell.KeyDown},
+			fn:      move(func(c, n int) int { return (c + 1) % n }),
+			swallow: true,
+		},
+		{
+			action:  "top",
+			keys:    []tcell.Key{tcell.KeyHome},
+			fn:      move(func(c, n int) int { return 0 }),
+			swallow: true,
+		},
+		{
+			action:  "bottom",
+			keys:    []tcell.Key{tcell.KeyEnd},
+			fn:      move(func(c, n int) int { return n - 1 }),
+			swallow: true,
+		},
+		{
+			action:  "page-up",
+			keys:    []tcell.Key{tcell.KeyPgUp},
+			fn:      move(func(c, n int) int { return clamp(c-pageSize, n) }),
+			swallow: true,
+		},
+		{
+			action:  "page-down",
+			keys:    []tcell.Key{tcell.KeyPgDn},
+			fn:      move(func(c, n int) int { return clamp(c+pageSize, n) }),
+			swallow: true,
+		},
+	}
+}
+
+// clamp limits the index to the range of a list of n items.
+func clamp(idx, n int) int {
+	if idx >= n {
+		idx = n - 1
+	}
+	if idx < 0 {
+		idx = 0
+	}
+	return idx
+}
+
+// fileInputs returns the inputs used to return to the file navigator,
+// which are only available when browsing a directory.
+func (i *inputHandler) fileInputs() []input {
+	if i.w.files == nil {
+		return nil
+	}
+
+	return []input{
+		{
+			action:  "files",
+			symbol:  " T ",
+			label:   "Files",
+			runes:   []rune{'t'},
+			fn:      i.w.showFiles,
+			swallow: true,
+		},
+	}
+}
+
+// helpInputs returns the input used to open the help overlay, which is
+// available in all focus modes.
+func (i *inputHandler) helpInputs() []input {
+	return []input{
+		{
+			action:  "help",
+			symbol:  " ? ",
+			label:   "Help",
+			runes:   []rune{'?'},
+			fn:      i.w.showHelp,
+			swallow: true,
+		},
+	}
+}
+
+// chapterInputs returns the input used to switch between displaying the
+// selected header alone and along with the headers nested under it.
+func (i *inputHandler) chapterInputs() []input {
+	return []input{
+		{
+			action:  "chapters",
+			runes:   []rune{'v'},
+			fn:      i.w.toggleChapters,
+			swallow: true,
+		},
+	}
+}
+
+// searchInputs returns the inputs used to search the document, which
+// are available in all focus modes.
+func (i *inputHandler) searchInputs() []input {
+	return []input{
+		{
+			action:  "search",
+			symbol:  " / ",
+			label:   "Search",
+			runes:   []rune{'/'},
+			fn:      i.w.startSearch,
+			swallow: true,
+		},
+		{
+			action:  "next-match",
+			runes:   []rune{'n'},
+			fn:      func() { i.w.nextMatch(1) },
+			swallow: true,
+		},
+		{
+			action:  "previous-match",
+			runes:   []rune{'N'},
+			fn:      func() { i.w.nextMatch(-1) },
+			swallow: true,
+		},
+	}
+}
+
+// historyInputs returns the inputs used to move back and forward between
+// documents, which are available in all focus modes.
+func (i *inputHandler) historyInputs() []input {
+	return []input{
+		{
+			action:  "history-back",
+			symbol:  " B ",
+			label:   "Back",
+			runes:   []rune{'b'},
+			fn:      func() { i.w.navigate(-1) },
+			swallow: true,
+		},
+		{
+			action:  "history-forward",
+			symbol:  " F ",
+			label:   "Forward",
+			runes:   []rune{'f'},
+			fn:      func() { i.w.navigate(1) },
+			swallow: true,
+		},
+	}
 }
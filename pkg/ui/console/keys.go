@@ -0,0 +1,340 @@
+package console
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell"
+)
+
+// Actions contains the name of each input that can be bound to other keys.
+var Actions = []string{
+	"exit", "up", "down", "top", "bottom", "page-up", "page-down",
+	"select", "open", "leave", "fold", "fold-level", "chapters",
+	"collapse", "copy",
+	"next-link", "previous-link", "open-link",
+	"search", "next-match", "previous-match",
+	"history-back", "history-forward", "files", "help",
+}
+
+// actionDescriptions describes each action in the help overlay.
+var actionDescriptions = map[string]string{
+	"exit":            "Exit",
+	"up":              "Move up",
+	"down":            "Move down",
+	"top":             "Move to the top",
+	"bottom":          "Move to the bottom",
+	"page-up":         "Move up a page",
+	"page-down":       "Move down a page",
+	"select":          "Show the content of the header",
+	"open":            "Open the file",
+	"leave":           "Go back",
+	"fold":            "Fold or unfold the headers nested under the header",
+	"fold-level":      "Fold the headers to the level of the digit or count, or 0 to unfold",
+	"collapse":        "Collapse or expand the section",
+	"copy":            "Copy the section to the clipboard",
+	"chapters":        "Show or hide the headers nested under the header",
+	"next-link":       "Select the next link",
+	"previous-link":   "Select the previous link",
+	"open-link":       "Open the selected link",
+	"search":          "Search the document",
+	"next-match":      "Go to the next search match",
+	"previous-match":  "Go to the previous search match",
+	"history-back":    "Go back to the previous document",
+	"history-forward": "Go forward to the next document",
+	"files":           "Show the file navigator",
+	"help":            "Show or hide this help",
+}
+
+// keyAliases contains names for keys in addition to tcell.KeyNames.
+var keyAliases = map[string]tcell.Key{
+	"escape":   tcell.KeyEscape,
+	"return":   tcell.KeyEnter,
+	"pagedown": tcell.KeyPgDn,
+	"pageup":   tcell.KeyPgUp,
+}
+
+// altPrefixes and ctrlPrefixes are the prefixes of key names that are
+// pressed with the Alt and Ctrl modifiers.
+var (
+	altPrefixes  = []string{"alt-", "meta-", "m-"}
+	ctrlPrefixes = []string{"c-"}
+)
+
+// stroke is a single key press within a key sequence.
+type stroke struct {
+	key tcell.Key
+	r   rune
+	alt bool
+}
+
+func newStroke(e *tcell.EventKey) stroke {
+	s := stroke{
+		key: e.Key(),
+		alt: e.Modifiers()&tcell.ModAlt != 0,
+	}
+	if s.key == tcell.KeyRune {
+		s.r = e.Rune()
+	}
+	return s
+}
+
+// String returns the name of the stroke, as accepted by parseStroke.
+func (s stroke) String() string {
+	var name string
+	switch {
+	case s.key == tcell.KeyRune && s.r == ' ':
+		name = "Space"
+	case s.key == tcell.KeyRune:
+		name = string(s.r)
+	default:
+		name = tcell.KeyNames[s.key]
+	}
+
+	if s.alt {
+		return "M-" + name
+	}
+	return name
+}
+
+// isCount returns true if the stroke is a digit that continues a count,
+// which can't begin with a zero.
+func (s stroke) isCount(count int) bool {
+	return s.key == tcell.KeyRune && !s.alt && s.r >= '0' && s.r <= '9' && (s.r != '0' || count > 0)
+}
+
+// keyBinding is the set of keys, runes and sequences an input is bound to.
+type keyBinding struct {
+	keys      []tcell.Key
+	runes     []rune
+	sequences [][]stroke
+	names     []string
+}
+
+// SetKeyBindings binds each action to the provided keys, replacing their
+// default keys or those of the current preset. See Actions for the available
+// actions.
+//
+// A key is either a single character, such as "q", or the name of a special
+// key such as "Enter", "Space", "PgDn" or "Ctrl-F", matched case-insensitively.
+// Keys pressed with Alt are prefixed with "M-" or "Alt-", such as "M-<", and
+// sequences of keys are separated by spaces, such as "g g" or "C-x C-c".
+func (w *Window) SetKeyBindings(bindings map[string][]string) error {
+	kb := make(map[string]keyBinding)
+	for action, keys := range bindings {
+		if !isAction(action) {
+			return fmt.Errorf("unknown action '%v'", action)
+		}
+		if len(keys) == 0 {
+			return fmt.Errorf("no keys provided for action '%v'", action)
+		}
+
+		b := keyBinding{names: keys}
+		for _, name := range keys {
+			seq, err := parseSequence(name)
+			if err != nil {
+				return fmt.Errorf("action '%v': %v", action, err)
+			}
+
+			switch {
+			case len(seq) > 1 || seq[0].alt:
+				b.sequences = append(b.sequences, seq)
+			case seq[0].key == tcell.KeyRune:
+				b.runes = append(b.runes, seq[0].r)
+			default:
+				b.keys = append(b.keys, seq[0].key)
+			}
+		}
+		kb[action] = b
+	}
+
+	previous := w.keyBindings
+	w.keyBindings = make(map[string]keyBinding)
+	for _, bindings := range []map[string]keyBinding{previous, kb} {
+		for action, b := range bindings {
+			w.keyBindings[action] = b
+		}
+	}
+
+	w.inputHandler.setInputs()
+	if err := w.inputHandler.conflict(); err != nil {
+		w.keyBindings = previous
+		w.inputHandler.setInputs()
+		return err
+	}
+	w.renderInputBar()
+	return nil
+}
+
+// conflict returns an error if different actions of the same focus mode
+// are bound to the same keys, or one is bound to the start of a sequence
+// that another is bound to.
+func (i *inputHandler) conflict() error {
+	for _, inputs := range [][]input{i.tableOfContents, i.content, i.files, i.help} {
+		for idx, in := range inputs {
+			for _, other := range inputs[idx+1:] {
+				if in.action == "" || other.action == "" || in.action == other.action {
+					continue
+				}
+
+				for _, a := range in.strokes() {
+					for _, b := range other.strokes() {
+						if hasStrokes(a, b) || hasStrokes(b, a) {
+							return fmt.Errorf("actions '%v' and '%v' are both bound to '%v'", in.action, other.action, sequenceName(a))
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// sequenceName returns the name of a sequence of strokes, as accepted by
+// parseSequence.
+func sequenceName(seq []stroke) string {
+	names := make([]string, len(seq))
+	for i, s := range seq {
+		names[i] = s.String()
+	}
+	return strings.Join(names, " ")
+}
+
+// strokes returns each of the keys the input is bound to as a sequence.
+func (i input) strokes() [][]stroke {
+	var seqs [][]stroke
+	for _, k := range i.keys {
+		seqs = append(seqs, []stroke{{key: k}})
+	}
+	for _, r := range i.runes {
+		seqs = append(seqs, []stroke{{key: tcell.KeyRune, r: r}})
+	}
+	return append(seqs, i.sequences...)
+}
+
+// bind replaces the keys and symbol of the input with the binding. Inputs
+// that are handled by the focused primitive forward their original event.
+func (i *input) bind(b keyBinding) {
+	if !i.swallow {
+		switch {
+		case len(i.keys) > 0:
+			i.forward = tcell.NewEventKey(i.keys[0], 0, tcell.ModNone)
+		case len(i.runes) > 0:
+			i.forward = tcell.NewEventKey(tcell.KeyRune, i.runes[0], tcell.ModNone)
+		}
+	}
+
+	i.keys = b.keys
+	i.runes = b.runes
+	i.sequences = b.sequences
+	if i.symbol != "" {
+		i.symbol = " " + strings.ToUpper(strings.Join(b.names, " / ")) + " "
+	}
+}
+
+// keyNames returns the name of each key, rune and sequence of the input.
+func (i input) keyNames() []string {
+	var names []string
+	for _, k := range i.keys {
+		names = append(names, stroke{key: k}.String())
+	}
+	for _, r := range i.runes {
+		names = append(names, stroke{key: tcell.KeyRune, r: r}.String())
+	}
+	for _, seq := range i.sequences {
+		strokes := make([]string, len(seq))
+		for j, s := range seq {
+			strokes[j] = s.String()
+		}
+		names = append(names, strings.Join(strokes, " "))
+	}
+	return names
+}
+
+// parseSequence returns the strokes of a space separated sequence of keys.
+func parseSequence(name string) ([]stroke, error) {
+	names := strings.Fields(name)
+	if len(names) == 0 {
+		return nil, fmt.Errorf("empty key")
+	}
+
+	seq := make([]stroke, len(names))
+	for i, n := range names {
+		s, err := parseStroke(n)
+		if err != nil {
+			return nil, err
+		}
+		seq[i] = s
+	}
+	return seq, nil
+}
+
+// parseStroke returns the key with the provided name, which is either a
+// single character or the name of a special key, optionally prefixed with
+// a modifier.
+func parseStroke(name string) (stroke, error) {
+	if utf8.RuneCountInString(name) == 1 {
+		r, _ := utf8.DecodeRuneInString(name)
+		return stroke{key: tcell.KeyRune, r: r}, nil
+	}
+
+	lower := strings.ToLower(name)
+	for _, p := range altPrefixes {
+		if strings.HasPrefix(lower, p) && len(name) > len(p) {
+			s, err := parseStroke(name[len(p):])
+			s.alt = true
+			return s, err
+		}
+	}
+	for _, p := range ctrlPrefixes {
+		if strings.HasPrefix(lower, p) && len(name) > len(p) {
+			lower = "ctrl-" + lower[len(p):]
+		}
+	}
+
+	if lower == "space" {
+		return stroke{key: tcell.KeyRune, r: ' '}, nil
+	}
+	if k, ok := keyAliases[lower]; ok {
+		return stroke{key: k}, nil
+	}
+
+	// Sort the keys so that a name shared by multiple keys, such as
+	// "Backspace" and "Ctrl-H", always resolves to the same key.
+	keys := make([]int, 0, len(tcell.KeyNames))
+	for k := range tcell.KeyNames {
+		keys = append(keys, int(k))
+	}
+	sort.Ints(keys)
+	for _, k := range keys {
+		if strings.ToLower(tcell.KeyNames[tcell.Key(k)]) == lower {
+			return stroke{key: tcell.Key(k)}, nil
+		}
+	}
+
+	return stroke{}, fmt.Errorf("unknown key '%v'", name)
+}
+
+// hasStrokes returns true if the sequence begins with the strokes.
+func hasStrokes(seq, strokes []stroke) bool {
+	if len(strokes) > len(seq) {
+		return false
+	}
+	for i, s := range strokes {
+		if seq[i] != s {
+			return false
+		}
+	}
+	return true
+}
+
+func isAction(name string) bool {
+	for _, a := range Actions {
+		if a == name {
+			return true
+		}
+	}
+	return false
+}
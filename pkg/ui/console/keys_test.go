@@ -0,0 +1,190 @@
+package console
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestParseSequence(t *testing.T) {
+	tests := []struct {
+		name      string
+		expect    []stroke
+		expectErr bool
+	}{
+		{"q", []stroke{{key: tcell.KeyRune, r: 'q'}}, false},
+		{"/", []stroke{{key: tcell.KeyRune, r: '/'}}, false},
+		{"Space", []stroke{{key: tcell.KeyRune, r: ' '}}, false},
+		{"enter", []stroke{{key: tcell.KeyEnter}}, false},
+		{"Esc", []stroke{{key: tcell.KeyEscape}}, false},
+		{"escape", []stroke{{key: tcell.KeyEscape}}, false},
+		{"ctrl-f", []stroke{{key: tcell.KeyCtrlF}}, false},
+		{"C-n", []stroke{{key: tcell.KeyCtrlN}}, false},
+		{"PgDn", []stroke{{key: tcell.KeyPgDn}}, false},
+		{"pagedown", []stroke{{key: tcell.KeyPgDn}}, false},
+		{"M-<", []stroke{{key: tcell.KeyRune, r: '<', alt: true}}, false},
+		{"Alt-Enter", []stroke{{key: tcell.KeyEnter, alt: true}}, false},
+		{"g g", []stroke{{key: tcell.KeyRune, r: 'g'}, {key: tcell.KeyRune, r: 'g'}}, false},
+		{"C-x C-c", []stroke{{key: tcell.KeyCtrlX}, {key: tcell.KeyCtrlC}}, false},
+		{"missing", nil, true},
+		{"g missing", nil, true},
+		{" ", nil, true},
+	}
+
+	for idx, tt := range tests {
+		seq, err := parseSequence(tt.name)
+		if (err != nil) != tt.expectErr {
+			t.Errorf("[%d] Unexpected error, expected=%v, got=%v", idx, tt.expectErr, err)
+			continue
+		}
+		if !reflect.DeepEqual(seq, tt.expect) {
+			t.Errorf("[%d] Unexpected sequence, expected=%v, got=%v", idx, tt.expect, seq)
+		}
+	}
+}
+
+func TestWindow_SetKeyBindings(t *testing.T) {
+	w := NewWindow()
+	w.RenderDocument(doc.Document{
+		Headers: []doc.Header{
+			{Title: "Header 1"},
+			{Title: "Header 2"},
+		},
+	})
+
+	if err := w.SetKeyBindings(map[string][]string{
+		"down":   {"j"},
+		"select": {"l", "Ctrl-L"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	w.inputHandler.handle(tcell.NewEventKey(tcell.KeyRune, 'j', tcell.ModNone))
+	if w.selectedHeader != 1 {
+		t.Errorf("Unexpected selectedHeader, expected=1, got=%v", w.selectedHeader)
+	}
+
+	w.inputHandler.handle(tcell.NewEventKey(tcell.KeyCtrlL, 0, tcell.ModNone))
+	if w.focusMode != focusContent {
+		t.Errorf("Unexpected focusMode, expected=%v, got=%v", focusContent, w.focusMode)
+	}
+
+	// Inputs that are also handled by the content body forward their
+	// original key.
+	got := w.inputHandler.handle(tcell.NewEventKey(tcell.KeyRune, 'j', tcell.ModNone))
+	if got == nil || got.Key() != tcell.KeyDown {
+		t.Errorf("Unexpected forwarded event, expected=%v, got=%v", tcell.KeyDown, got)
+	}
+
+	for _, in := range w.inputHandler.tableOfContents {
+		if in.action == "select" && in.symbol != " L / CTRL-L " {
+			t.Errorf("Unexpected symbol, expected=%q, got=%q", " L / CTRL-L ", in.symbol)
+		}
+	}
+
+	// Invalid bindings
+	tests := []map[string][]string{
+		{"missing": {"q"}},
+		{"exit": {"missing"}},
+		{"exit": {}},
+		{"copy": {"n"}},
+		{"top": {"c c"}},
+	}
+	for idx, tt := range tests {
+		if err := w.SetKeyBindings(tt); err == nil {
+			t.Errorf("[%d] Expected an error", idx)
+		}
+	}
+
+	// Conflicting bindings leave the previous bindings in place
+	for _, in := range w.inputHandler.content {
+		if in.action == "copy" && string(in.runes) != "c" {
+			t.Errorf("Unexpected copy keys, expected=c, got=%v", string(in.runes))
+		}
+	}
+}
+
+func TestWindow_SetKeyPreset_conflicts(t *testing.T) {
+	for name := range Presets {
+		if err := NewWindow().SetKeyPreset(name); err != nil {
+			t.Errorf("Unexpected error for preset %v: %v", name, err)
+		}
+	}
+}
+
+func TestWindow_SetKeyPreset(t *testing.T) {
+	w := NewWindow()
+	w.RenderDocument(doc.Document{
+		Headers: []doc.Header{
+			{Title: "Header 1", Content: []doc.Section{
+				{Spans: []doc.Span{{Text: "1"}}},
+				{Spans: []doc.Span{{Text: "2"}}},
+				{Spans: []doc.Span{{Text: "3"}}},
+			}},
+			{Title: "Header 2"},
+			{Title: "Header 3"},
+			{Title: "Header 4"},
+		},
+	})
+	if err := w.SetKeyPreset("vim"); err != nil {
+		t.Fatal(err)
+	}
+
+	type key struct {
+		key tcell.Key
+		r   rune
+		mod tcell.ModMask
+	}
+	tests := []struct {
+		keys          []key
+		expectFocus   focusMode
+		expectHeader  int
+		expectSection int
+	}{
+		{[]key{{tcell.KeyRune, 'j', 0}}, focusTableOfContents, 1, 0},
+		{[]key{{tcell.KeyRune, '2', 0}, {tcell.KeyRune, 'j', 0}}, focusTableOfContents, 3, 0},
+		{[]key{{tcell.KeyRune, 'g', 0}, {tcell.KeyRune, 'g', 0}}, focusTableOfContents, 0, 0},
+		{[]key{{tcell.KeyRune, 'G', 0}}, focusTableOfContents, 3, 0},
+		{[]key{{tcell.KeyRune, 'g', 0}, {tcell.KeyRune, 'k', 0}}, focusTableOfContents, 2, 0},
+		{[]key{{tcell.KeyHome, 0, 0}, {tcell.KeyRune, 'l', 0}}, focusContent, 0, 0},
+		{[]key{{tcell.KeyCtrlD, 0, 0}}, focusContent, 0, 2},
+		{[]key{{tcell.KeyRune, '1', 0}, {tcell.KeyRune, '0', 0}, {tcell.KeyRune, 'k', 0}}, focusContent, 0, 1},
+		{[]key{{tcell.KeyRune, 'h', 0}}, focusTableOfContents, 0, 1},
+	}
+
+	for idx, tt := range tests {
+		for _, k := range tt.keys {
+			w.inputHandler.handle(tcell.NewEventKey(k.key, k.r, k.mod))
+		}
+
+		if w.focusMode != tt.expectFocus {
+			t.Errorf("[%d] Unexpected focusMode, expected=%v, got=%v", idx, tt.expectFocus, w.focusMode)
+		}
+		if w.selectedHeader != tt.expectHeader {
+			t.Errorf("[%d] Unexpected selectedHeader, expected=%v, got=%v", idx, tt.expectHeader, w.selectedHeader)
+		}
+		if w.selectedSection != tt.expectSection {
+			t.Errorf("[%d] Unexpected selectedSection, expected=%v, got=%v", idx, tt.expectSection, w.selectedSection)
+		}
+	}
+
+	// Emacs sequences with the Alt modifier
+	if err := w.SetKeyPreset("emacs"); err != nil {
+		t.Fatal(err)
+	}
+	w.inputHandler.handle(tcell.NewEventKey(tcell.KeyRune, '>', tcell.ModAlt))
+	if w.selectedHeader != 3 {
+		t.Errorf("Unexpected selectedHeader, expected=3, got=%v", w.selectedHeader)
+	}
+	w.inputHandler.handle(tcell.NewEventKey(tcell.KeyRune, '<', tcell.ModAlt))
+	if w.selectedHeader != 0 {
+		t.Errorf("Unexpected selectedHeader, expected=0, got=%v", w.selectedHeader)
+	}
+
+	if err := w.SetKeyPreset("missing"); err == nil {
+		t.Error("Expected an error for a missing preset")
+	}
+}
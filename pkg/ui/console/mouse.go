@@ -0,0 +1,196 @@
+package console
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+// minTableOfContentsWidth and minContentWidth limit resizing by the divider.
+const (
+	minTableOfContentsWidth = 10
+	minContentWidth         = 20
+)
+
+// clickTextView is a TextView that reports the region that's clicked.
+type clickTextView struct {
+	*tview.TextView
+
+	clicked func(region string)
+}
+
+func (t *clickTextView) MouseHandler() func(tview.MouseAction, *tcell.EventMouse, func(tview.Primitive)) (bool, tview.Primitive) {
+	handler := t.TextView.MouseHandler()
+	return func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(tview.Primitive)) (bool, tview.Primitive) {
+		if action != tview.MouseLeftClick {
+			return handler(action, event, setFocus)
+		}
+
+		highlights := t.GetHighlights()
+		t.Highlight()
+		consumed, capture := handler(action, event, setFocus)
+
+		var region string
+		if clicked := t.GetHighlights(); len(clicked) > 0 {
+			region = clicked[0]
+		} else {
+			t.Highlight(highlights...)
+		}
+		if consumed {
+			t.clicked(region)
+		}
+		return consumed, capture
+	}
+}
+
+// handleMouse resizes the table of contents and scrolls lists, passing
+// other events on to the primitive that's under the mouse.
+func (w *Window) handleMouse(event *tcell.EventMouse, action tview.MouseAction) (*tcell.EventMouse, tview.MouseAction) {
+	// Actions that follow a consumed action are passed without an event.
+	if event == nil {
+		if action == tview.MouseLeftUp {
+			w.resizing = false
+		}
+		return nil, action
+	}
+
+	x, y := event.Position()
+	if w.resizing {
+		switch action {
+		case tview.MouseMove:
+			tx, _, _, _ := w.tableOfContents.GetRect()
+			w.setTableOfContentsWidth(x - tx)
+		case tview.MouseLeftUp:
+			w.resizing = false
+		}
+		return nil, action
+	}
+
+	switch w.inputHandler.focus {
+	case focusTableOfContents, focusContent:
+	case focusFiles:
+		return w.handleListMouse(w.fileList, event, action, w.openFile), action
+	case focusSearch:
+		return nil, action
+	case focusHelp:
+		if !w.helpView.InRect(x, y) {
+			return nil, action
+		}
+		return event, action
+	default:
+		return event, action
+	}
+
+	if w.singlePage {
+		return event, action
+	}
+	if action == tview.MouseLeftDown && w.isDivider(x, y) {
+		w.resizing = true
+		return nil, action
+	}
+	if !w.tableOfContents.InRect(x, y) {
+		return event, action
+	}
+
+	switch action {
+	case tview.MouseLeftClick, tview.MouseScrollUp, tview.MouseScrollDown:
+		if w.focusMode != focusTableOfContents {
+			w.setFocusMode(focusTableOfContents)
+		}
+	}
+	return w.handleListMouse(w.tableOfContents, event, action, func(int) {
+		w.setFocusMode(focusContent)
+	}), action
+}
+
+// handleListMouse scrolls a list and opens the item that's double clicked.
+func (w *Window) handleListMouse(l *tview.List, event *tcell.EventMouse, action tview.MouseAction, open func(idx int)) *tcell.EventMouse {
+	n := l.GetItemCount()
+	if n == 0 || !l.InRect(event.Position()) {
+		return event
+	}
+
+	switch action {
+	case tview.MouseScrollUp:
+		l.SetCurrentItem(clamp(l.GetCurrentItem()-1, n))
+	case tview.MouseScrollDown:
+		l.SetCurrentItem(clamp(l.GetCurrentItem()+1, n))
+	case tview.MouseLeftDoubleClick:
+		open(l.GetCurrentItem())
+	default:
+		return event
+	}
+	return nil
+}
+
+// contentClicked selects the section that's clicked and follows its link.
+func (w *Window) contentClicked(region string) {
+	section, link := w.regionSection(region)
+	if !w.isValidSectionIndex(section) {
+		w.restoreFocus()
+		return
+	}
+
+	w.selectSection(section)
+	if link >= 0 {
+		w.selectedLink = link
+		w.openLink()
+	}
+}
+
+// regionSection returns the section and link of a region, or -1.
+func (w *Window) regionSection(region string) (section, link int) {
+	if _, err := fmt.Sscanf(region, "link-%d-%d", &section, &link); err == nil {
+		return section, link
+	}
+
+	var m int
+	if _, err := fmt.Sscanf(region, "match-%d", &m); err == nil {
+		if w.search == nil || m < 0 || m >= len(w.search.matches) {
+			return -1, -1
+		}
+		match := w.search.matches[m]
+		return w.blockIndex(match.header, match.section), -1
+	}
+
+	if section, err := strconv.Atoi(region); err == nil {
+		return section, -1
+	}
+	return -1, -1
+}
+
+// selectSection selects a section without scrolling it into view.
+func (w *Window) selectSection(idx int) {
+	if w.focusMode != focusContent {
+		w.focusMode = focusContent
+		w.SetFocus(w.contentBody)
+		w.inputHandler.setFocusMode(focusContent)
+		w.renderInputBar()
+	}
+
+	w.selectedSection = idx
+	w.selectedLink = -1
+	w.contentBody.Highlight(fmt.Sprintf("%d", idx))
+}
+
+// isDivider returns true if the position is on the divider.
+func (w *Window) isDivider(x, y int) bool {
+	tx, ty, width, height := w.tableOfContents.GetRect()
+	return x == tx+width && y >= ty && y < ty+height
+}
+
+// setTableOfContentsWidth resizes the table of contents within limits.
+func (w *Window) setTableOfContentsWidth(width int) {
+	_, _, total, _ := w.layout.GetRect()
+	if max := total - minContentWidth; width > max {
+		width = max
+	}
+	if width < minTableOfContentsWidth {
+		width = minTableOfContentsWidth
+	}
+
+	w.tableOfContentsWidth = width
+	w.renderLayout()
+}
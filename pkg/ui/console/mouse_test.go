@@ -0,0 +1,185 @@
+package console
+
+import (
+	"testing"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+// drawWindow draws the window to a simulated screen, laying out its
+// primitives as they're displayed.
+func drawWindow(t *testing.T, w *Window) {
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	width, height := screen.Size()
+	w.pages.SetRect(0, 0, width, height)
+	w.pages.Draw(screen)
+}
+
+// mouse dispatches a mouse event at the position as the application does.
+func mouse(w *Window, action tview.MouseAction, x, y int) {
+	event := tcell.NewEventMouse(x, y, tcell.ButtonNone, tcell.ModNone)
+	if event, action = w.handleMouse(event, action); event == nil {
+		return
+	}
+	w.pages.MouseHandler()(action, event, func(p tview.Primitive) {
+		w.SetFocus(p)
+	})
+}
+
+func TestWindow_handleMouse_tableOfContents(t *testing.T) {
+	w := NewWindow()
+	w.RenderDocument(outlineDoc)
+	drawWindow(t, w)
+	x, y, _, _ := w.tableOfContents.GetInnerRect()
+
+	// Each item is followed by its secondary text.
+	mouse(w, tview.MouseLeftClick, x+8, y+4)
+	if w.selectedHeader != 2 {
+		t.Errorf("Unexpected selectedHeader, expected=2, got=%v", w.selectedHeader)
+	}
+	if w.focusMode != focusTableOfContents {
+		t.Errorf("Unexpected focusMode, expected=%v, got=%v", focusTableOfContents, w.focusMode)
+	}
+
+	// Double clicking a header shows its content.
+	mouse(w, tview.MouseLeftDoubleClick, x+8, y+4)
+	if w.focusMode != focusContent {
+		t.Errorf("Unexpected focusMode after double clicking, expected=%v, got=%v", focusContent, w.focusMode)
+	}
+
+	mouse(w, tview.MouseScrollDown, x, y)
+	if w.selectedHeader != 3 {
+		t.Errorf("Unexpected selectedHeader after scrolling, expected=3, got=%v", w.selectedHeader)
+	}
+	if w.focusMode != focusTableOfContents {
+		t.Errorf("Unexpected focusMode after scrolling, expected=%v, got=%v", focusTableOfContents, w.focusMode)
+	}
+}
+
+func TestWindow_handleMouse_content(t *testing.T) {
+	w := NewWindow()
+	w.RenderDocument(doc.Document{
+		Headers: []doc.Header{
+			{Title: "Install", Level: 1, Content: []doc.Section{
+				{Spans: []doc.Span{{Text: "go get\n"}}},
+				{Spans: []doc.Span{
+					{Text: "See "},
+					{Style: doc.Link, Link: "#usage", Children: []doc.Span{{Text: "usage"}}},
+					{Text: "\n"},
+				}},
+			}},
+			{Title: "Usage", Level: 1, Anchor: "usage"},
+		},
+	})
+	drawWindow(t, w)
+	x, y, _, _ := w.contentBody.GetInnerRect()
+
+	// Clicking a section selects it.
+	mouse(w, tview.MouseLeftClick, x+1, y+2)
+	if w.focusMode != focusContent {
+		t.Errorf("Unexpected focusMode, expected=%v, got=%v", focusContent, w.focusMode)
+	}
+	if w.selectedSection != 1 {
+		t.Errorf("Unexpected selectedSection, expected=1, got=%v", w.selectedSection)
+	}
+	if w.selectedHeader != 0 {
+		t.Errorf("Unexpected selectedHeader, expected=0, got=%v", w.selectedHeader)
+	}
+
+	// Clicking a link follows it.
+	drawWindow(t, w)
+	mouse(w, tview.MouseLeftClick, x+5, y+2)
+	if w.selectedHeader != 1 {
+		t.Errorf("Unexpected selectedHeader after clicking a link, expected=1, got=%v", w.selectedHeader)
+	}
+}
+
+func TestWindow_handleMouse_wheel(t *testing.T) {
+	content := make([]doc.Section, 50)
+	for i := range content {
+		content[i] = doc.Section{Spans: []doc.Span{{Text: "Text\n"}}}
+	}
+
+	w := NewWindow()
+	w.RenderDocument(doc.Document{Headers: []doc.Header{{Title: "Header", Content: content}}})
+	drawWindow(t, w)
+	x, y, _, _ := w.contentBody.GetInnerRect()
+
+	mouse(w, tview.MouseScrollDown, x, y)
+	drawWindow(t, w)
+	if row, _ := w.contentBody.GetScrollOffset(); row != 1 {
+		t.Errorf("Unexpected scroll offset, expected=1, got=%v", row)
+	}
+
+	// Clicks account for the scroll offset.
+	mouse(w, tview.MouseLeftClick, x, y+1)
+	if w.selectedSection != 1 {
+		t.Errorf("Unexpected selectedSection, expected=1, got=%v", w.selectedSection)
+	}
+}
+
+func TestWindow_handleMouse_resize(t *testing.T) {
+	w := NewWindow()
+	w.RenderDocument(outlineDoc)
+	drawWindow(t, w)
+	tx, ty, width, _ := w.tableOfContents.GetRect()
+
+	tests := []struct {
+		x      int
+		expect int
+	}{
+		{tx + 40, 40},
+		{tx + 2, minTableOfContentsWidth},
+		{tx + 75, 80 - minContentWidth},
+	}
+
+	mouse(w, tview.MouseLeftDown, tx+width, ty)
+	for idx, tt := range tests {
+		mouse(w, tview.MouseMove, tt.x, ty)
+		if w.tableOfContentsWidth != tt.expect {
+			t.Errorf("[%d] Unexpected width, expected=%v, got=%v", idx, tt.expect, w.tableOfContentsWidth)
+		}
+	}
+
+	mouse(w, tview.MouseLeftUp, tx+30, ty)
+	if w.resizing {
+		t.Error("Expected resizing to stop when the button is released")
+	}
+}
+
+func TestWindow_handleMouse_help(t *testing.T) {
+	w := NewWindow()
+	w.RenderDocument(outlineDoc)
+	w.showHelp()
+	drawWindow(t, w)
+	x, y, _, _ := w.tableOfContents.GetInnerRect()
+
+	// Clicks outside of the help overlay are ignored.
+	mouse(w, tview.MouseLeftClick, x, y+4)
+	if w.selectedHeader != 0 {
+		t.Errorf("Unexpected selectedHeader, expected=0, got=%v", w.selectedHeader)
+	}
+	if w.inputHandler.focus != focusHelp {
+		t.Errorf("Unexpected input focus, expected=%v, got=%v", focusHelp, w.inputHandler.focus)
+	}
+}
+
+func TestWindow_handleMouse_nil(t *testing.T) {
+	w := NewWindow()
+	w.RenderDocument(outlineDoc)
+	w.resizing = true
+
+	if event, _ := w.handleMouse(nil, tview.MouseLeftUp); event != nil {
+		t.Errorf("Unexpected event, expected=nil, got=%v", event)
+	}
+	if w.resizing {
+		t.Error("Expected resizing to stop when the button is released")
+	}
+}
@@ -0,0 +1,62 @@
+package console
+
+import "fmt"
+
+// Preset is a named set of key bindings. See Window.SetKeyBindings.
+type Preset struct {
+	Bindings map[string][]string
+
+	// Counts enables a number typed before a key to repeat its action,
+	// such as "5j" to move down five times.
+	Counts bool
+}
+
+// Presets contains the key binding presets that can be applied with
+// Window.SetKeyPreset. The "default" preset uses the arrow keys.
+var Presets = map[string]Preset{
+	"default": {},
+	"vim": {
+		Bindings: map[string][]string{
+			"exit":      {"q", "Esc"},
+			"up":        {"k", "Up"},
+			"down":      {"j", "Down"},
+			"top":       {"g g", "Home"},
+			"bottom":    {"G", "End"},
+			"page-up":   {"Ctrl-U", "PgUp"},
+			"page-down": {"Ctrl-D", "PgDn"},
+			"select":    {"l", "Right", "Enter"},
+			"open":      {"l", "Right", "Enter"},
+			"leave":     {"h", "Left", "Esc"},
+			// Digits are counts, so "2z" folds to the second level.
+			"fold-level": {"z"},
+		},
+		Counts: true,
+	},
+	"emacs": {
+		Bindings: map[string][]string{
+			"exit":      {"C-x C-c", "Esc"},
+			"up":        {"C-p", "Up"},
+			"down":      {"C-n", "Down"},
+			"top":       {"M-<", "Home"},
+			"bottom":    {"M->", "End"},
+			"page-up":   {"M-v", "PgUp"},
+			"page-down": {"C-v", "PgDn"},
+			"select":    {"C-f", "Right", "Enter"},
+			"open":      {"C-f", "Right", "Enter"},
+			"leave":     {"C-b", "Left", "Esc"},
+			"search":    {"C-s", "/"},
+		},
+	},
+}
+
+// SetKeyPreset replaces the key bindings with those of a preset.
+func (w *Window) SetKeyPreset(name string) error {
+	p, ok := Presets[name]
+	if !ok {
+		return fmt.Errorf("unknown key preset '%v'", name)
+	}
+
+	w.keyBindings = nil
+	w.keyCounts = p.Counts
+	return w.SetKeyBindings(p.Bindings)
+}
@@ -0,0 +1,142 @@
+package console
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+
+	"github.com/rivo/tview"
+)
+
+// mark is a range of a section's text that is placed in a region of
+// its own, so that it can be highlighted independently of the section.
+type mark struct {
+	// start and end are the offsets of the mark within the section's
+	// text, excluding newlines.
+	start int
+	end   int
+
+	region string
+
+	// style, if set, replaces the styling of the marked text.
+	style *Style
+}
+
+// visibleOffset converts an offset within a section's text to an offset
+// that excludes newlines, as used by marks.
+func visibleOffset(text string, offset int) int {
+	return offset - strings.Count(text[:offset], "\n")
+}
+
+// regionStyler wraps a doc.Styler, placing each marked range of a section
+// in its own region. When marks overlap, the mark that appears later in
+// the list takes priority.
+type regionStyler struct {
+	doc.Styler
+
+	region string
+	marks  []mark
+
+	// offset is the number of characters styled so far, and current is
+	// the region that the styled text currently belongs to.
+	offset  int
+	current string
+}
+
+// newRegionStyler returns a regionStyler for the section with the
+// provided region ID.
+func newRegionStyler(st doc.Styler, region string, marks []mark) *regionStyler {
+	return &regionStyler{
+		Styler:  st,
+		region:  region,
+		marks:   marks,
+		current: region,
+	}
+}
+
+// Style styles the text using the underlying Styler, starting a new
+// region for each mark within the text.
+func (r *regionStyler) Style(str string, ds doc.Style) string {
+	start, end := r.offset, r.offset+len(str)
+	r.offset = end
+
+	bounds := []int{start, end}
+	for _, m := range r.marks {
+		for _, b := range []int{m.start, m.end} {
+			if b > start && b < end {
+				bounds = append(bounds, b)
+			}
+		}
+	}
+	sort.Ints(bounds)
+
+	var buf bytes.Buffer
+	for i := 0; i < len(bounds)-1; i++ {
+		from, to := bounds[i], bounds[i+1]
+		if from == to {
+			continue
+		}
+
+		region := r.region
+		m := r.markAt(from)
+		if m != nil {
+			region = m.region
+		}
+		if region != r.current {
+			buf.WriteString(fmt.Sprintf(`["%v"]`, region))
+			r.current = region
+		}
+
+		text := str[from-start : to-start]
+		if m != nil && m.style != nil {
+			buf.WriteString(fmt.Sprintf("[%v:%v:%v]", m.style.FgColor, m.style.BgColor, m.style.TextStyle))
+			buf.WriteString(tview.Escape(text))
+			buf.WriteString("[-:-:-]")
+		} else {
+			buf.WriteString(r.Styler.Style(text, ds))
+		}
+	}
+
+	// The section's region is returned to before the next styled text,
+	// see renderContentBody.
+	return buf.String()
+}
+
+// markAt returns the mark with the highest priority that contains the
+// offset, or nil if there is none.
+func (r *regionStyler) markAt(offset int) *mark {
+	for i := len(r.marks) - 1; i >= 0; i-- {
+		if m := r.marks[i]; offset >= m.start && offset < m.end {
+			return &r.marks[i]
+		}
+	}
+	return nil
+}
+
+// linkMarks returns the marks placing each link within a section in
+// its own region.
+func linkMarks(section int, s doc.Section) []mark {
+	links := s.Links()
+	if len(links) == 0 {
+		return nil
+	}
+
+	text := s.Text()
+	marks := make([]mark, len(links))
+	for i, l := range links {
+		marks[i] = mark{
+			start:  visibleOffset(text, l.Start),
+			end:    visibleOffset(text, l.End),
+			region: linkRegion(section, i),
+		}
+	}
+	return marks
+}
+
+// linkRegion returns the region ID of a link within a section.
+func linkRegion(section, idx int) string {
+	return fmt.Sprintf("link-%d-%d", section, idx)
+}
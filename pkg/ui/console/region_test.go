@@ -0,0 +1,58 @@
+package console
+
+import (
+	"testing"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+)
+
+func TestVisibleOffset(t *testing.T) {
+	text := "One\nTwo\nThree"
+
+	tests := []struct {
+		offset int
+		expect int
+	}{
+		{0, 0},
+		{3, 3},
+		{4, 3},
+		{8, 6},
+		{len(text), 11},
+	}
+
+	for idx, tt := range tests {
+		if got := visibleOffset(text, tt.offset); got != tt.expect {
+			t.Errorf("[%d] Unexpected offset, expected=%v, got=%v", idx, tt.expect, got)
+		}
+	}
+}
+
+func TestRegionStyler_Style(t *testing.T) {
+	s := newSearch(searchDoc, "install")
+	r := newRegionStyler(doc.NopStyler{}, "0", s.marks(1, 0))
+
+	got := searchDoc.Headers[1].Content[0].Render(r)
+	expect := "First line\nthen [\"match-2\"][black:yellow:]Install[-:-:-][\"0\"] again\n"
+	if got != expect {
+		t.Errorf("Unexpected render, expected=%q, got=%q", expect, got)
+	}
+}
+
+func TestRegionStyler_Style_overlapping(t *testing.T) {
+	section := doc.Section{Spans: []doc.Span{
+		{Text: "See "},
+		{Style: doc.Link, Link: "guide.md", Children: []doc.Span{{Text: "guide"}}},
+	}}
+
+	marks := []mark{
+		{start: 4, end: 20, region: "link-0-0"},
+		{start: 6, end: 8, region: "match-0", style: &matchStyle},
+	}
+	r := newRegionStyler(doc.NopStyler{}, "0", marks)
+
+	got := section.Render(r)
+	expect := `See ["link-0-0"]gu["match-0"][black:yellow:]id[-:-:-]["link-0-0"]e <guide.md>` + "\n"
+	if got != expect {
+		t.Errorf("Unexpected render, expected=%q, got=%q", expect, got)
+	}
+}
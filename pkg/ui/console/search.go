@@ -0,0 +1,156 @@
+package console
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+
+	"github.com/rivo/tview"
+)
+
+// titleMatch is the section index of a match found in a header's title.
+const titleMatch = -1
+
+// matchStyle is applied to the text of search matches.
+var matchStyle = Style{"black", "yellow", "", ""}
+
+// match is the location of a search match within a document.
+type match struct {
+	header  int
+	section int
+
+	// start and end are the offsets of the match within the section's
+	// rendered text, excluding newlines.
+	start int
+	end   int
+}
+
+// search contains the matches of a query within a document.
+type search struct {
+	query   string
+	matches []match
+	current int
+}
+
+// newSearch finds all case-insensitive matches of the query within
+// the titles and sections of a document.
+func newSearch(d doc.Document, query string) *search {
+	s := search{
+		query:   query,
+		current: -1,
+	}
+	if query == "" {
+		return &s
+	}
+
+	q, _ := lower(query)
+	for h, header := range d.Headers {
+		if title, _ := lower(header.Title); strings.Contains(title, q) {
+			s.matches = append(s.matches, match{header: h, section: titleMatch})
+		}
+
+		for i, section := range header.Content {
+			var offset int
+			for _, line := range strings.Split(section.Text(), "\n") {
+				l, offsets := lower(line)
+				for start := 0; ; {
+					idx := strings.Index(l[start:], q)
+					if idx < 0 {
+						break
+					}
+
+					start += idx
+					s.matches = append(s.matches, match{
+						header:  h,
+						section: i,
+						start:   offset + offsets[start],
+						end:     offset + offsets[start+len(q)],
+					})
+					start += len(q)
+				}
+				offset += len(line)
+			}
+		}
+	}
+
+	return &s
+}
+
+// lower returns the text in lower case, along with the offset within the
+// text of each byte of the result, as lowering can change the length of
+// a character.
+func lower(text string) (string, []int) {
+	var buf strings.Builder
+	offsets := make([]int, 0, len(text)+1)
+	for i, r := range text {
+		n := buf.Len()
+		buf.WriteRune(unicode.ToLower(r))
+		for ; n < buf.Len(); n++ {
+			offsets = append(offsets, i)
+		}
+	}
+	offsets = append(offsets, len(text))
+	return buf.String(), offsets
+}
+
+// next advances to the next (or previous, if the delta is negative) match,
+// wrapping around the ends of the document.
+func (s *search) next(delta int) (match, bool) {
+	if len(s.matches) == 0 {
+		return match{}, false
+	}
+
+	s.current = (s.current + delta) % len(s.matches)
+	if s.current < 0 {
+		s.current += len(s.matches)
+	}
+	return s.matches[s.current], true
+}
+
+// seek positions the search so that the next match is the first one
+// at or after the provided header.
+func (s *search) seek(header int) {
+	s.current = -1
+	for i, m := range s.matches {
+		if m.header >= header {
+			return
+		}
+		s.current = i
+	}
+}
+
+// String returns a summary of the search for display in the input bar.
+func (s *search) String() string {
+	if len(s.matches) == 0 {
+		return fmt.Sprintf("No matches for '%v'", tview.Escape(s.query))
+	}
+
+	current := s.current + 1
+	if current < 1 {
+		current = 1
+	}
+	return fmt.Sprintf("'%v' %d/%d (n/N)", tview.Escape(s.query), current, len(s.matches))
+}
+
+// marks returns the marks highlighting the matches within a section.
+func (s *search) marks(header, section int) []mark {
+	var marks []mark
+	for i, m := range s.matches {
+		if m.header == header && m.section == section {
+			marks = append(marks, mark{
+				start:  m.start,
+				end:    m.end,
+				region: matchRegion(i),
+				style:  &matchStyle,
+			})
+		}
+	}
+	return marks
+}
+
+// matchRegion returns the region ID of a match.
+func matchRegion(idx int) string {
+	return fmt.Sprintf("match-%d", idx)
+}
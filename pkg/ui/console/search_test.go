@@ -0,0 +1,132 @@
+package console
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+
+	"github.com/gdamore/tcell"
+)
+
+var searchDoc = doc.Document{
+	Headers: []doc.Header{
+		{Title: "Install", Content: []doc.Section{
+			{Spans: []doc.Span{{Text: "Run go get to install.\n"}}},
+		}},
+		{Title: "Usage", Content: []doc.Section{
+			{Spans: []doc.Span{{Text: "First line\nthen "}, {Text: "Install", Style: doc.Bold}, {Text: " again"}}},
+		}},
+	},
+}
+
+func TestNewSearch(t *testing.T) {
+	s := newSearch(searchDoc, "install")
+
+	expect := []match{
+		{header: 0, section: titleMatch},
+		{header: 0, section: 0, start: 14, end: 21},
+		{header: 1, section: 0, start: 15, end: 22},
+	}
+	if !reflect.DeepEqual(s.matches, expect) {
+		t.Errorf("Unexpected matches, expected=%v, got=%v", expect, s.matches)
+	}
+
+	// Characters that change length when lowered
+	s = newSearch(doc.Document{Headers: []doc.Header{
+		{Title: "Ⱥ", Content: []doc.Section{{Spans: []doc.Span{{Text: "ȺȺ Kurz\n"}}}}},
+	}}, "KURZ")
+	expect = []match{{header: 0, section: 0, start: 5, end: 9}}
+	if !reflect.DeepEqual(s.matches, expect) {
+		t.Errorf("Unexpected matches after lowering, expected=%v, got=%v", expect, s.matches)
+	}
+
+	// No query
+	s = newSearch(searchDoc, "")
+	if len(s.matches) != 0 {
+		t.Errorf("Unexpected matches for empty query, got=%v", s.matches)
+	}
+}
+
+func TestSearch_next(t *testing.T) {
+	s := newSearch(searchDoc, "install")
+
+	tests := []struct {
+		delta  int
+		expect int
+	}{
+		{1, 0},
+		{1, 1},
+		{1, 2},
+		{1, 0},
+		{-1, 2},
+		{-1, 1},
+	}
+
+	for idx, tt := range tests {
+		if _, ok := s.next(tt.delta); !ok {
+			t.Fatalf("[%d] Expected a match", idx)
+		}
+		if s.current != tt.expect {
+			t.Errorf("[%d] Unexpected current, expected=%v, got=%v", idx, tt.expect, s.current)
+		}
+	}
+
+	// Seek to a header
+	s.seek(1)
+	if m, _ := s.next(1); m.header != 1 {
+		t.Errorf("Unexpected header after seek, expected=1, got=%v", m.header)
+	}
+
+	// No matches
+	s = newSearch(searchDoc, "missing")
+	if _, ok := s.next(1); ok {
+		t.Error("Unexpected match for missing query")
+	}
+}
+
+func TestWindow_nextMatch(t *testing.T) {
+	w := NewWindow()
+	w.RenderDocument(searchDoc)
+
+	w.setSearch("again")
+	w.nextMatch(1)
+
+	if w.selectedHeader != 1 {
+		t.Errorf("Unexpected selectedHeader, expected=1, got=%v", w.selectedHeader)
+	}
+	if w.focusMode != focusContent {
+		t.Errorf("Unexpected focusMode, expected=%v, got=%v", focusContent, w.focusMode)
+	}
+	if got := w.contentBody.GetRegionText(matchRegion(0)); got != "again\n\n" {
+		t.Errorf("Unexpected match region text, got=%q", got)
+	}
+
+	// Clearing the search
+	w.setSearch("")
+	if w.search != nil {
+		t.Error("Expected search to be cleared")
+	}
+}
+
+func TestWindow_searchDoneHandler(t *testing.T) {
+	w := NewWindow()
+	w.RenderDocument(doc.Document{Headers: []doc.Header{
+		{Title: "Header", Content: []doc.Section{
+			{Spans: []doc.Span{{Text: "One\n"}}},
+			{Spans: []doc.Span{{Text: "Two\n"}}},
+		}},
+	}})
+	w.setFocusMode(focusContent)
+	w.setSelectedSection(1)
+
+	// Cancelling the search keeps the selected section
+	w.startSearch()
+	w.searchDoneHandler(tcell.KeyEscape)
+	if w.selectedSection != 1 {
+		t.Errorf("Unexpected selectedSection, expected=1, got=%v", w.selectedSection)
+	}
+	if w.inputHandler.focus != focusContent {
+		t.Errorf("Unexpected input focus, expected=%v, got=%v", focusContent, w.inputHandler.focus)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"github.com/KyleBanks/kurz/pkg/doc"
+
+	"github.com/rivo/tview"
 )
 
 var StyleMap = map[doc.Style]Style{
@@ -18,10 +20,100 @@ var StyleMap = map[doc.Style]Style{
 	doc.Image:      Style{"#9331ee", "", "bu", ""},
 	doc.Link:       Style{"green", "", "bu", ""},
 	doc.Unknown:    Style{"red", "", "", ""},
+
+	doc.Keyword:   Style{"#cc7832", "", "b", ""},
+	doc.Plain:     Style{"", "", "", ""},
+	doc.Constant:  Style{"#9876aa", "", "b", ""},
+	doc.String:    Style{"#6a8759", "", "", ""},
+	doc.Number:    Style{"#6897bb", "", "", ""},
+	doc.Comment:   Style{"#808080", "", "", ""},
+	doc.Operator:  Style{"#a9b7c6", "", "", ""},
+	doc.Attribute: Style{"#ffc66d", "", "", ""},
 }
 
 var DefaultStyle Style
 
+// StyleNames contains the name of each doc.Style that can be configured.
+var StyleNames = map[string]doc.Style{
+	"normal":     doc.Normal,
+	"bold":       doc.Bold,
+	"italic":     doc.Italic,
+	"underline":  doc.Underline,
+	"blockquote": doc.BlockQuote,
+	"code":       doc.Code,
+	"codeblock":  doc.CodeBlock,
+	"image":      doc.Image,
+	"link":       doc.Link,
+	"unknown":    doc.Unknown,
+
+	"keyword":   doc.Keyword,
+	"plain":     doc.Plain,
+	"constant":  doc.Constant,
+	"string":    doc.String,
+	"number":    doc.Number,
+	"comment":   doc.Comment,
+	"operator":  doc.Operator,
+	"attribute": doc.Attribute,
+}
+
+// Themes contains the named sets of styles that can be applied with
+// SetTheme. The "dark" theme is applied by default.
+var Themes = map[string]map[doc.Style]Style{
+	"dark": copyStyles(StyleMap),
+	"light": {
+		doc.Bold:       Style{"", "", "b", ""},
+		doc.Italic:     Style{"", "", "u", ""},
+		doc.Underline:  Style{"", "", "u", ""},
+		doc.BlockQuote: Style{"", "", "b", "   "},
+		doc.Code:       Style{"purple", "", "b", ""},
+		doc.CodeBlock:  Style{"purple", "", "b", "   "},
+		doc.Image:      Style{"#6a0dad", "", "bu", ""},
+		doc.Link:       Style{"navy", "", "bu", ""},
+		doc.Unknown:    Style{"maroon", "", "", ""},
+
+		doc.Keyword:   Style{"#0033b3", "", "b", ""},
+		doc.Plain:     Style{"", "", "", ""},
+		doc.Constant:  Style{"#871094", "", "b", ""},
+		doc.String:    Style{"#067d17", "", "", ""},
+		doc.Number:    Style{"#1750eb", "", "", ""},
+		doc.Comment:   Style{"#8c8c8c", "", "", ""},
+		doc.Operator:  Style{"", "", "", ""},
+		doc.Attribute: Style{"#871094", "", "", ""},
+	},
+	"mono": {
+		doc.Bold:       Style{"", "", "b", ""},
+		doc.Italic:     Style{"", "", "u", ""},
+		doc.Underline:  Style{"", "", "u", ""},
+		doc.BlockQuote: Style{"", "", "b", "   "},
+		doc.Code:       Style{"", "", "b", ""},
+		doc.CodeBlock:  Style{"", "", "", "   "},
+		doc.Image:      Style{"", "", "bu", ""},
+		doc.Link:       Style{"", "", "u", ""},
+		doc.Keyword:    Style{"", "", "b", ""},
+	},
+}
+
+// SetTheme replaces the StyleMap with a copy of the styles of a theme.
+func SetTheme(name string) error {
+	t, ok := Themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme '%v'", name)
+	}
+
+	StyleMap = copyStyles(t)
+	return nil
+}
+
+// copyStyles returns a copy of a set of styles, so that changes to the
+// StyleMap don't affect the theme it was set from.
+func copyStyles(styles map[doc.Style]Style) map[doc.Style]Style {
+	c := make(map[doc.Style]Style, len(styles))
+	for ds, s := range styles {
+		c[ds] = s
+	}
+	return c
+}
+
 type Style struct {
 	FgColor   string
 	BgColor   string
@@ -32,17 +124,13 @@ type Style struct {
 type Styler struct{}
 
 func (Styler) Style(str string, ds doc.Style) string {
-	s, ok := StyleMap[ds]
-	if !ok {
-		s = DefaultStyle
-	}
+	s := lookupStyle(ds)
 
 	lines := strings.Split(str, "\n")
 	var buf bytes.Buffer
 	for i, l := range lines {
-		buf.WriteString(s.Indent)
 		buf.WriteString(fmt.Sprintf("[%v:%v:%v]", s.FgColor, s.BgColor, s.TextStyle))
-		buf.WriteString(l)
+		buf.WriteString(tview.Escape(l))
 		buf.WriteString("[-:-:-]")
 
 		if i < len(lines)-1 {
@@ -51,3 +139,16 @@ func (Styler) Style(str string, ds doc.Style) string {
 	}
 	return buf.String()
 }
+
+func (Styler) Indent(ds doc.Style) string {
+	return lookupStyle(ds).Indent
+}
+
+// lookupStyle returns the Style mapped to a doc.Style, or the DefaultStyle.
+func lookupStyle(ds doc.Style) Style {
+	s, ok := StyleMap[ds]
+	if !ok {
+		s = DefaultStyle
+	}
+	return s
+}
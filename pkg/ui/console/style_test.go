@@ -0,0 +1,58 @@
+package console
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+)
+
+func TestSetTheme(t *testing.T) {
+	original := StyleMap
+	defer func() { StyleMap = original }()
+
+	for name, styles := range Themes {
+		if err := SetTheme(name); err != nil {
+			t.Errorf("Unexpected error for theme %v: %v", name, err)
+		}
+		if !reflect.DeepEqual(StyleMap, styles) {
+			t.Errorf("Unexpected StyleMap for theme %v, expected=%v, got=%v", name, styles, StyleMap)
+		}
+	}
+
+	if err := SetTheme("missing"); err == nil {
+		t.Error("Expected an error for a missing theme")
+	}
+
+	// Changing the StyleMap leaves the theme intact
+	SetTheme("dark")
+	StyleMap[doc.Bold] = Style{FgColor: "red"}
+	if Themes["dark"][doc.Bold] == StyleMap[doc.Bold] {
+		t.Error("Expected the dark theme to be unchanged")
+	}
+}
+
+func TestStyler_Style(t *testing.T) {
+	original := StyleMap
+	defer func() { StyleMap = original }()
+
+	StyleMap = map[doc.Style]Style{
+		doc.Bold: {TextStyle: "b"},
+	}
+
+	tests := []struct {
+		text   string
+		style  doc.Style
+		expect string
+	}{
+		{"text", doc.Bold, "[::b]text[-:-:-]"},
+		{"a\nb", doc.Bold, "[::b]a[-:-:-]\n[::b]b[-:-:-]"},
+		{"[x]", doc.Normal, "[::][x[][-:-:-]"},
+	}
+
+	for idx, tt := range tests {
+		if got := (Styler{}).Style(tt.text, tt.style); got != tt.expect {
+			t.Errorf("[%d] Unexpected style, expected=%q, got=%q", idx, tt.expect, got)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package console
+
+import (
+	"strings"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+
+	"github.com/rivo/tview"
+)
+
+const (
+	foldedSymbol   = "▸ "
+	unfoldedSymbol = "▾ "
+	leafSymbol     = "  "
+
+	breadcrumbSeparator = " › "
+)
+
+// outline is the nesting of a document's headers, where each header is
+// the parent of the headers of a deeper level that follow it, up to the
+// next header of the same or a higher level.
+type outline struct {
+	// parents contains the index of each header's parent, or -1 for
+	// headers at the top of the outline.
+	parents []int
+}
+
+// newOutline returns the outline of the headers. The preamble is never
+// the parent of other headers, as it doesn't appear in the document.
+func newOutline(headers []doc.Header) outline {
+	o := outline{parents: make([]int, len(headers))}
+
+	var stack []int
+	for i, h := range headers {
+		for len(stack) > 0 && headers[stack[len(stack)-1]].Level >= h.Level {
+			stack = stack[:len(stack)-1]
+		}
+
+		o.parents[i] = -1
+		if len(stack) > 0 {
+			o.parents[i] = stack[len(stack)-1]
+		}
+		if !h.Preamble {
+			stack = append(stack, i)
+		}
+	}
+	return o
+}
+
+// hasChildren returns true if any headers are nested under the header,
+// in which case the first of them directly follows it.
+func (o outline) hasChildren(idx int) bool {
+	return idx+1 < len(o.parents) && o.parents[idx+1] == idx
+}
+
+// ancestors returns the parent of the header, its parent, and so on up
+// to the top of the outline, starting with the top.
+func (o outline) ancestors(idx int) []int {
+	var a []int
+	for p := o.parents[idx]; p >= 0; p = o.parents[p] {
+		a = append([]int{p}, a...)
+	}
+	return a
+}
+
+// depth returns the number of headers the header is nested under, plus
+// one, so that headers at the top of the outline have a depth of 1.
+func (o outline) depth(idx int) int {
+	return len(o.ancestors(idx)) + 1
+}
+
+// rows returns the index of each header that isn't nested under one of
+// the folded headers.
+func (o outline) rows(folded map[int]bool) []int {
+	var rows []int
+	for i := 0; i < len(o.parents); i++ {
+		rows = append(rows, i)
+		if folded[i] {
+			i = o.end(i) - 1
+		}
+	}
+	return rows
+}
+
+// end returns the index of the first header after the header that isn't
+// nested under it.
+func (o outline) end(idx int) int {
+	end := idx + 1
+	for end < len(o.parents) && o.isNested(end, idx) {
+		end++
+	}
+	return end
+}
+
+// isNested returns true if the header is nested under the ancestor at
+// any depth.
+func (o outline) isNested(idx, ancestor int) bool {
+	for p := o.parents[idx]; p >= 0; p = o.parents[p] {
+		if p == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// renderTableOfContents lists each visible header of the outline, indented
+// by its depth and marked as folded or unfolded if it has nested headers.
+// The selected header, or its nearest visible ancestor if it's been folded
+// away, remains selected.
+func (w *Window) renderTableOfContents() {
+	w.outline = newOutline(w.doc.Headers)
+	w.tableOfContentsRows = w.outline.rows(w.contentState.folded)
+
+	selected := w.selectedHeader
+	if selected >= len(w.doc.Headers) {
+		selected = 0
+	}
+	row := 0
+
+	w.tableOfContents.Clear()
+	for r, idx := range w.tableOfContentsRows {
+		h := w.doc.Headers[idx]
+		text := h.Title
+		if h.Level <= 2 {
+			text = Styler{}.Style(text, doc.Bold)
+		}
+
+		symbol := leafSymbol
+		if w.outline.hasChildren(idx) && w.contentState.folded[idx] {
+			symbol = foldedSymbol
+		} else if w.outline.hasChildren(idx) {
+			symbol = unfoldedSymbol
+		}
+		text = strings.Repeat("  ", w.outline.depth(idx)-1) + symbol + text
+
+		if idx == selected || (idx < selected && w.outline.isNested(selected, idx)) {
+			row = r
+		}
+		w.tableOfContents.AddItem(text, "", 0, nil)
+	}
+
+	if len(w.tableOfContentsRows) > 0 {
+		w.tableOfContents.SetCurrentItem(row)
+	}
+}
+
+// selectTableOfContentsHeader selects a header in the table of contents,
+// unfolding its ancestors if it's nested under a folded header.
+func (w *Window) selectTableOfContentsHeader(idx int) {
+	if idx < 0 || idx >= len(w.doc.Headers) {
+		return
+	}
+
+	var unfolded bool
+	for _, a := range w.outline.ancestors(idx) {
+		if w.contentState.folded[a] {
+			delete(w.contentState.folded, a)
+			unfolded = true
+		}
+	}
+	if unfolded {
+		w.renderTableOfContents()
+	}
+
+	for r, h := range w.tableOfContentsRows {
+		if h == idx {
+			w.tableOfContents.SetCurrentItem(r)
+			return
+		}
+	}
+}
+
+// toggleFold folds the headers nested under the selected header, or
+// unfolds them if they're already folded.
+func (w *Window) toggleFold() {
+	idx := w.selectedHeader
+	if !w.outline.hasChildren(idx) {
+		return
+	}
+
+	if w.contentState.folded[idx] {
+		delete(w.contentState.folded, idx)
+	} else {
+		w.contentState.folded[idx] = true
+	}
+	w.renderTableOfContents()
+}
+
+// foldToLevel folds every header at the depth of the level or deeper,
+// so that only the top levels of the outline are visible. A level of zero
+// unfolds every header.
+func (w *Window) foldToLevel(level int) {
+	w.contentState.folded = make(map[int]bool)
+	for i := range w.outline.parents {
+		if level > 0 && w.outline.hasChildren(i) && w.outline.depth(i) >= level {
+			w.contentState.folded[i] = true
+		}
+	}
+	w.renderTableOfContents()
+}
+
+// breadcrumb returns the titles of the selected header and its ancestors,
+// or an empty string if it isn't nested under another header.
+func (w *Window) breadcrumb() string {
+	if w.singlePage || w.selectedHeader >= len(w.outline.parents) {
+		return ""
+	}
+
+	ancestors := w.outline.ancestors(w.selectedHeader)
+	if len(ancestors) == 0 {
+		return ""
+	}
+
+	titles := make([]string, 0, len(ancestors)+1)
+	for _, idx := range append(ancestors, w.selectedHeader) {
+		titles = append(titles, tview.Escape(w.doc.Headers[idx].Title))
+	}
+	return strings.Join(titles, breadcrumbSeparator)
+}
+
+func (w *Window) tableOfContentsSelectionHandler(index int, mainText, secondaryText string, shortcut rune) {
+	if index < 0 || index >= len(w.tableOfContentsRows) {
+		return
+	}
+
+	w.setSelectedHeader(w.tableOfContentsRows[index])
+	w.renderInputBar()
+}
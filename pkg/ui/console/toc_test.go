@@ -0,0 +1,206 @@
+package console
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+
+	"github.com/gdamore/tcell"
+)
+
+// outlineDoc is a document with nested headers:
+//
+//	0 Preamble
+//	1 Title
+//	2   Install
+//	3     Linux
+//	4     macOS
+//	5   Usage
+//	6     Flags
+//	7 License
+var outlineDoc = doc.Document{
+	Headers: []doc.Header{
+		{Title: "Preamble", Level: 1, Preamble: true},
+		{Title: "Title", Level: 1},
+		{Title: "Install", Level: 2},
+		{Title: "Linux", Level: 3},
+		{Title: "macOS", Level: 3},
+		{Title: "Usage", Level: 2},
+		{Title: "Flags", Level: 4},
+		{Title: "License", Level: 1},
+	},
+}
+
+func TestNewOutline(t *testing.T) {
+	o := newOutline(outlineDoc.Headers)
+
+	expect := []int{-1, -1, 1, 2, 2, 1, 5, -1}
+	if !reflect.DeepEqual(o.parents, expect) {
+		t.Errorf("Unexpected parents, expected=%v, got=%v", expect, o.parents)
+	}
+}
+
+func TestOutline_rows(t *testing.T) {
+	o := newOutline(outlineDoc.Headers)
+
+	tests := []struct {
+		folded map[int]bool
+		expect []int
+	}{
+		{nil, []int{0, 1, 2, 3, 4, 5, 6, 7}},
+		{map[int]bool{2: true}, []int{0, 1, 2, 5, 6, 7}},
+		{map[int]bool{1: true}, []int{0, 1, 7}},
+		{map[int]bool{1: true, 5: true}, []int{0, 1, 7}},
+		{map[int]bool{3: true, 6: true}, []int{0, 1, 2, 3, 4, 5, 6, 7}},
+	}
+
+	for idx, tt := range tests {
+		if got := o.rows(tt.folded); !reflect.DeepEqual(got, tt.expect) {
+			t.Errorf("[%d] Unexpected rows, expected=%v, got=%v", idx, tt.expect, got)
+		}
+	}
+}
+
+func TestOutline_ancestors(t *testing.T) {
+	o := newOutline(outlineDoc.Headers)
+
+	tests := []struct {
+		idx    int
+		expect []int
+	}{
+		{0, nil},
+		{1, nil},
+		{3, []int{1, 2}},
+		{6, []int{1, 5}},
+		{7, nil},
+	}
+
+	for idx, tt := range tests {
+		if got := o.ancestors(tt.idx); !reflect.DeepEqual(got, tt.expect) {
+			t.Errorf("[%d] Unexpected ancestors, expected=%v, got=%v", idx, tt.expect, got)
+		}
+		if got := o.depth(tt.idx); got != len(tt.expect)+1 {
+			t.Errorf("[%d] Unexpected depth, expected=%v, got=%v", idx, len(tt.expect)+1, got)
+		}
+	}
+}
+
+func TestWindow_toggleFold(t *testing.T) {
+	w := NewWindow()
+	w.RenderDocument(outlineDoc)
+	w.tableOfContents.SetCurrentItem(2)
+
+	w.toggleFold()
+	if n := w.tableOfContents.GetItemCount(); n != 6 {
+		t.Errorf("Unexpected item count after folding, expected=6, got=%v", n)
+	}
+	if w.selectedHeader != 2 {
+		t.Errorf("Unexpected selectedHeader after folding, expected=2, got=%v", w.selectedHeader)
+	}
+
+	w.toggleFold()
+	if n := w.tableOfContents.GetItemCount(); n != 8 {
+		t.Errorf("Unexpected item count after unfolding, expected=8, got=%v", n)
+	}
+
+	// Headers without nested headers can't be folded.
+	w.tableOfContents.SetCurrentItem(3)
+	w.toggleFold()
+	if len(w.contentState.folded) != 0 {
+		t.Errorf("Unexpected folded headers, expected none, got=%v", w.contentState.folded)
+	}
+}
+
+func TestWindow_foldToLevel(t *testing.T) {
+	w := NewWindow()
+	w.RenderDocument(outlineDoc)
+	w.tableOfContents.SetCurrentItem(6)
+
+	tests := []struct {
+		level  int
+		rows   []int
+		header int
+	}{
+		{2, []int{0, 1, 2, 5, 7}, 5},
+		{1, []int{0, 1, 7}, 1},
+		{0, []int{0, 1, 2, 3, 4, 5, 6, 7}, 1},
+	}
+
+	for idx, tt := range tests {
+		w.foldToLevel(tt.level)
+		if !reflect.DeepEqual(w.tableOfContentsRows, tt.rows) {
+			t.Errorf("[%d] Unexpected rows, expected=%v, got=%v", idx, tt.rows, w.tableOfContentsRows)
+		}
+
+		// A selected header that's folded away is replaced by its nearest
+		// visible ancestor.
+		if w.selectedHeader != tt.header {
+			t.Errorf("[%d] Unexpected selectedHeader, expected=%v, got=%v", idx, tt.header, w.selectedHeader)
+		}
+	}
+}
+
+func TestWindow_selectTableOfContentsHeader(t *testing.T) {
+	w := NewWindow()
+	w.RenderDocument(outlineDoc)
+	w.foldToLevel(1)
+
+	w.selectTableOfContentsHeader(4)
+	if w.selectedHeader != 4 {
+		t.Errorf("Unexpected selectedHeader, expected=4, got=%v", w.selectedHeader)
+	}
+	if expect := []int{0, 1, 2, 3, 4, 5, 7}; !reflect.DeepEqual(w.tableOfContentsRows, expect) {
+		t.Errorf("Unexpected rows, expected=%v, got=%v", expect, w.tableOfContentsRows)
+	}
+	if expect := 4; w.tableOfContents.GetCurrentItem() != expect {
+		t.Errorf("Unexpected current item, expected=%v, got=%v", expect, w.tableOfContents.GetCurrentItem())
+	}
+}
+
+func TestWindow_breadcrumb(t *testing.T) {
+	w := NewWindow()
+	w.RenderDocument(outlineDoc)
+
+	tests := []struct {
+		header int
+		expect string
+	}{
+		{1, ""},
+		{3, "Title › Install › Linux"},
+		{6, "Title › Usage › Flags"},
+		{7, ""},
+	}
+
+	for idx, tt := range tests {
+		w.tableOfContents.SetCurrentItem(tt.header)
+		if got := w.breadcrumb(); got != tt.expect {
+			t.Errorf("[%d] Unexpected breadcrumb, expected=%v, got=%v", idx, tt.expect, got)
+		}
+	}
+}
+
+func TestInputHandler_foldLevel(t *testing.T) {
+	tests := []struct {
+		preset string
+		keys   []rune
+	}{
+		{"default", []rune{'2'}},
+		{"vim", []rune{'2', 'z'}},
+	}
+
+	for idx, tt := range tests {
+		w := NewWindow()
+		w.RenderDocument(outlineDoc)
+		if err := w.SetKeyPreset(tt.preset); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, r := range tt.keys {
+			w.inputHandler.handle(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+		}
+		if expect := []int{0, 1, 2, 5, 7}; !reflect.DeepEqual(w.tableOfContentsRows, expect) {
+			t.Errorf("[%d] Unexpected rows, expected=%v, got=%v", idx, expect, w.tableOfContentsRows)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+// Package export provides ui.Canvas types that write documents to files
+// in other formats, allowing them to be shared without kurz.
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+	"github.com/KyleBanks/kurz/pkg/doc/resolver"
+	"github.com/KyleBanks/kurz/pkg/ui/console"
+
+	"github.com/gdamore/tcell"
+)
+
+// pageColors are the foreground and background colors of the page.
+var pageColors = map[bool][2]string{
+	false: {"#1f1f1f", "#ffffff"},
+	true:  {"#d4d4d4", "#1e1e1e"},
+}
+
+// baseCSS lays out the page with the table of contents in a sidebar.
+const baseCSS = `* { box-sizing: border-box; }
+body { margin: 0; display: flex; font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-size: 14px; line-height: 1.5; color: var(--fg); background: var(--bg); }
+nav { position: sticky; top: 0; flex: 0 0 18rem; height: 100vh; overflow-y: auto; padding: 1rem; border-right: 1px solid; }
+nav a { display: block; color: inherit; text-decoration: none; white-space: nowrap; overflow: hidden; text-overflow: ellipsis; }
+nav a:hover { text-decoration: underline; }
+main { flex: 1; min-width: 0; padding: 1rem 2rem; }
+summary { cursor: pointer; }
+summary > * { display: inline; font-size: 1em; }
+.section { margin: 1em 0; white-space: pre-wrap; overflow-wrap: break-word; }
+.section-code { overflow-x: auto; white-space: pre; }
+table { border-collapse: collapse; }
+th, td { padding: 0 0.5em; border: 1px solid; }
+img { max-width: 100%; }
+hr { width: 40ch; margin-left: 0; }
+`
+
+// HTML renders documents as a single self-contained HTML page.
+type HTML struct {
+	Out io.Writer
+
+	// Dark selects the page colors of a dark terminal.
+	Dark bool
+
+	err error
+}
+
+// RenderDocument writes the document to the output, styled with the
+// console.StyleMap. See Err.
+func (h *HTML) RenderDocument(d doc.Document) {
+	w := bufio.NewWriter(h.Out)
+	colors := pageColors[h.Dark]
+
+	fmt.Fprintf(w, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(w, "<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	fmt.Fprintf(w, "<title>%v</title>\n", html.EscapeString(d.Title))
+	fmt.Fprintf(w, "<style>\n:root { --fg: %v; --bg: %v; }\n", colors[0], colors[1])
+	io.WriteString(w, baseCSS)
+	io.WriteString(w, styleCSS())
+	io.WriteString(w, "</style>\n")
+	fmt.Fprintf(w, "</head>\n<body>\n")
+
+	fmt.Fprintf(w, "<nav>\n")
+	for i, hd := range d.Headers {
+		writeTOCEntry(w, i, hd)
+	}
+	fmt.Fprintf(w, "</nav>\n")
+
+	// Sections are nested so that collapsing a header collapses its children.
+	fmt.Fprintf(w, "<main>\n")
+	var open []int
+	for i, hd := range d.Headers {
+		level := hd.Level
+		if level < 1 || level > 6 {
+			level = 1
+		}
+		for len(open) > 0 && open[len(open)-1] >= level {
+			fmt.Fprintf(w, "</details>\n")
+			open = open[:len(open)-1]
+		}
+
+		fmt.Fprintf(w, "<details id=\"%v\" open>\n", html.EscapeString(anchor(i, hd)))
+		fmt.Fprintf(w, "<summary><h%d>%v</h%d></summary>\n", level, html.EscapeString(hd.Title), level)
+		for _, s := range hd.Content {
+			writeSection(w, d, s)
+		}
+		open = append(open, level)
+	}
+	for range open {
+		fmt.Fprintf(w, "</details>\n")
+	}
+	fmt.Fprintf(w, "</main>\n")
+
+	// Open collapsed sections when they're navigated to.
+	fmt.Fprintf(w, "<script>\n%v</script>\n", openScript)
+	fmt.Fprintf(w, "</body>\n</html>\n")
+
+	if err := w.Flush(); err != nil && h.err == nil {
+		h.err = err
+	}
+}
+
+// Err returns the first error that occurred while writing.
+func (h *HTML) Err() error {
+	return h.err
+}
+
+const openScript = `function openTarget() {
+  var el = document.getElementById(decodeURIComponent(location.hash.slice(1)));
+  for (; el; el = el.parentElement) {
+    if (el.tagName === "DETAILS") el.open = true;
+  }
+}
+window.addEventListener("hashchange", openTarget);
+openTarget();
+`
+
+// writeTOCEntry writes a link to a header, laid out as in the console.
+func writeTOCEntry(w io.Writer, idx int, h doc.Header) {
+	title := html.EscapeString(h.Title)
+	if h.Level <= 2 {
+		title = "<span class=\"bold\">" + title + "</span>"
+	}
+
+	indent := h.Level - 1
+	if indent < 0 {
+		indent = 0
+	}
+	fmt.Fprintf(w, "<a href=\"#%v\" style=\"padding-left: %dch\">%v</a>\n", html.EscapeString(url.PathEscape(anchor(idx, h))), indent, title)
+}
+
+// anchor returns the identifier of a header within the page.
+func anchor(idx int, h doc.Header) string {
+	if h.Anchor != "" {
+		return h.Anchor
+	}
+	return fmt.Sprintf("section-%d", idx)
+}
+
+// writeSection writes a section as a block of preformatted text.
+func writeSection(w io.Writer, d doc.Document, s doc.Section) {
+	switch {
+	case s.Kind == doc.RuleSection:
+		fmt.Fprintf(w, "<hr>\n")
+		return
+	case s.Kind == doc.TableSection && s.Table != nil:
+		writeTable(w, d, *s.Table)
+		return
+	}
+
+	class := "section section-" + s.Kind.String()
+	if s.Kind == doc.QuoteSection {
+		class += " " + styleClass(doc.BlockQuote)
+	}
+	fmt.Fprintf(w, "<div class=\"%v\">", class)
+	for _, sp := range s.Spans {
+		writeSpan(w, d, sp)
+	}
+	fmt.Fprintf(w, "</div>\n")
+}
+
+// writeTable writes a table, with each column aligned as in the console.
+func writeTable(w io.Writer, d doc.Document, t doc.Table) {
+	fmt.Fprintf(w, "<table class=\"section\">\n")
+	for _, row := range t.Rows {
+		tag := "td"
+		if row.Header {
+			tag = "th"
+		}
+
+		fmt.Fprintf(w, "<tr>")
+		for c, cell := range row.Cells {
+			align := "left"
+			if c < len(t.Align) {
+				switch t.Align[c] {
+				case doc.AlignCenter:
+					align = "center"
+				case doc.AlignRight:
+					align = "right"
+				}
+			}
+
+			fmt.Fprintf(w, "<%v style=\"text-align: %v\">", tag, align)
+			for _, sp := range cell {
+				writeSpan(w, d, sp)
+			}
+			fmt.Fprintf(w, "</%v>", tag)
+		}
+		fmt.Fprintf(w, "</tr>\n")
+	}
+	fmt.Fprintf(w, "</table>\n")
+}
+
+// writeSpan writes a span and its children, wrapped in an element
+// with the class of its Style.
+func writeSpan(w io.Writer, d doc.Document, sp doc.Span) {
+	dest := linkDestination(d.Source, sp.Link)
+	switch {
+	case sp.Style == doc.Image && dest != "":
+		fmt.Fprintf(w, "<img class=\"%v\" src=\"%v\" alt=\"%v\">", styleClass(sp.Style), html.EscapeString(dest), html.EscapeString(spanText(sp)))
+		return
+	case sp.Style == doc.Link && dest != "":
+		fmt.Fprintf(w, "<a class=\"%v\" href=\"%v\">", styleClass(sp.Style), html.EscapeString(dest))
+	case sp.Style != doc.Normal:
+		fmt.Fprintf(w, "<span class=\"%v\">", styleClass(sp.Style))
+	}
+
+	io.WriteString(w, html.EscapeString(sp.Text))
+	for _, c := range sp.Children {
+		writeSpan(w, d, c)
+	}
+
+	switch {
+	case sp.Style == doc.Link && dest != "":
+		io.WriteString(w, "</a>")
+	case sp.Style != doc.Normal:
+		io.WriteString(w, "</span>")
+	}
+}
+
+// spanText returns the text of a span and its children.
+func spanText(sp doc.Span) string {
+	text := sp.Text
+	for _, c := range sp.Children {
+		text += spanText(c)
+	}
+	return text
+}
+
+// linkDestination returns the destination of a link within the page,
+// resolving relative links against a remote source. Links with a scheme
+// other than http, https or mailto are dropped.
+func linkDestination(source, link string) string {
+	if link == "" || strings.HasPrefix(link, "#") {
+		return link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	switch u.Scheme {
+	case "":
+	case "http", "https", "mailto":
+		return link
+	default:
+		return ""
+	}
+
+	joined := resolver.Join(source, link)
+	if u, err := url.Parse(joined); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		return joined
+	}
+	return link
+}
+
+// styleClass returns the CSS class of a doc.Style.
+func styleClass(ds doc.Style) string {
+	for name, s := range console.StyleNames {
+		if s == ds {
+			return name
+		}
+	}
+	return ""
+}
+
+// styleCSS returns the CSS rules of each style in the console.StyleMap.
+func styleCSS() string {
+	var names []string
+	for name := range console.StyleNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		ds := console.StyleNames[name]
+		st, ok := console.StyleMap[ds]
+		if !ok {
+			continue
+		}
+
+		// The console underlines italics in place of displaying them.
+		if ds == doc.Italic {
+			st.TextStyle = strings.Replace(st.TextStyle, "u", "", -1)
+		}
+
+		decls := styleDeclarations(st)
+		if ds == doc.Italic {
+			decls = append(decls, "font-style: italic")
+		}
+		if len(decls) > 0 {
+			fmt.Fprintf(&b, ".%v { %v; }\n", name, strings.Join(decls, "; "))
+		}
+	}
+	return b.String()
+}
+
+// styleDeclarations returns the CSS declarations of a console Style.
+func styleDeclarations(st console.Style) []string {
+	fg, bg := cssColor(st.FgColor), cssColor(st.BgColor)
+	if strings.ContainsRune(st.TextStyle, 'r') {
+		fg, bg = bg, fg
+		if fg == "" {
+			fg = "var(--bg)"
+		}
+		if bg == "" {
+			bg = "var(--fg)"
+		}
+	}
+
+	var decls []string
+	if fg != "" {
+		decls = append(decls, "color: "+fg)
+	}
+	if bg != "" {
+		decls = append(decls, "background-color: "+bg)
+	}
+	if strings.ContainsRune(st.TextStyle, 'b') {
+		decls = append(decls, "font-weight: bold")
+	}
+	if strings.ContainsRune(st.TextStyle, 'd') {
+		decls = append(decls, "opacity: 0.7")
+	}
+	if strings.ContainsRune(st.TextStyle, 'u') {
+		decls = append(decls, "text-decoration: underline")
+	}
+	if n := len(st.Indent); n > 0 {
+		decls = append(decls, fmt.Sprintf("display: block; padding-left: %dch", n))
+	}
+	return decls
+}
+
+// cssColor returns the hex value of a color name, or an empty string.
+func cssColor(name string) string {
+	if name == "" {
+		return ""
+	}
+
+	c := tcell.GetColor(name)
+	if c == tcell.ColorDefault || c.Hex() < 0 {
+		return ""
+	}
+	return fmt.Sprintf("#%06x", c.Hex())
+}
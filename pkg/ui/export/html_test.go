@@ -0,0 +1,171 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+	"github.com/KyleBanks/kurz/pkg/ui/console"
+)
+
+var testDoc = doc.Document{
+	Title:  "README.md",
+	Source: "https://example.com/docs/README.md",
+	Headers: []doc.Header{
+		{Title: "kurz", Preamble: true, Content: []doc.Section{
+			{Spans: []doc.Span{{Text: "Intro <text>.\n"}}},
+		}},
+		{Title: "Usage", Level: 2, Anchor: "usage", Content: []doc.Section{
+			{Spans: []doc.Span{
+				{Text: "See the "},
+				{Text: "guide", Style: doc.Link, Link: "guide.md"},
+				{Text: " or "},
+				{Text: "usage", Style: doc.Link, Link: "#usage"},
+				{Text: ".\n"},
+			}},
+			{Kind: doc.CodeSection, Spans: []doc.Span{{Style: doc.CodeBlock, Children: []doc.Span{
+				{Text: "func", Style: doc.Keyword}, {Text: " main() {}"},
+			}}}},
+			{Kind: doc.TableSection, Table: &doc.Table{
+				Align: []doc.Alignment{doc.AlignLeft, doc.AlignRight},
+				Rows: []doc.TableRow{
+					{Header: true, Cells: [][]doc.Span{{{Text: "Flag"}}, {{Text: "Default"}}}},
+					{Cells: [][]doc.Span{{{Text: "--width"}}, {{Text: "0"}}}},
+				},
+			}},
+			{Kind: doc.RuleSection},
+		}},
+		{Title: "Options", Level: 3, Anchor: "options"},
+	},
+}
+
+func TestHTML_RenderDocument(t *testing.T) {
+	var out bytes.Buffer
+	h := HTML{Out: &out}
+	h.RenderDocument(testDoc)
+	if h.Err() != nil {
+		t.Fatalf("Unexpected error, got=%v", h.Err())
+	}
+
+	expect := []string{
+		"<title>README.md</title>",
+
+		// Table of contents
+		`<a href="#section-0" style="padding-left: 0ch"><span class="bold">kurz</span></a>`,
+		`<a href="#usage" style="padding-left: 1ch"><span class="bold">Usage</span></a>`,
+		`<a href="#options" style="padding-left: 2ch">Options</a>`,
+
+		// Collapsible sections
+		"<details id=\"section-0\" open>\n<summary><h1>kurz</h1></summary>",
+		"<details id=\"usage\" open>\n<summary><h2>Usage</h2></summary>",
+		"<summary><h3>Options</h3></summary>\n</details>\n</details>\n</details>\n</main>",
+
+		// Content
+		`<div class="section section-paragraph">Intro &lt;text&gt;.`,
+		`<a class="link" href="https://example.com/docs/guide.md">guide</a>`,
+		`<a class="link" href="#usage">usage</a>`,
+		`<div class="section section-code"><span class="codeblock"><span class="keyword">func</span> main() {}</span></div>`,
+		`<tr><th style="text-align: left">Flag</th><th style="text-align: right">Default</th></tr>`,
+		"<hr>",
+	}
+	for idx, e := range expect {
+		if !strings.Contains(out.String(), e) {
+			t.Errorf("[%d] Expected output to contain %q, got=%v", idx, e, out.String())
+		}
+	}
+}
+
+func TestHTML_RenderDocument_unsafeLinks(t *testing.T) {
+	d := doc.Document{
+		Source: "https://example.com/docs/README.md",
+		Headers: []doc.Header{{Title: "kurz", Content: []doc.Section{{Spans: []doc.Span{
+			{Text: "click", Style: doc.Link, Link: "javascript:alert(1)"},
+			{Style: doc.Image, Link: "data:text/html;base64,PHNjcmlwdD4=", Children: []doc.Span{{Text: "logo"}}},
+		}}}}},
+	}
+
+	var out bytes.Buffer
+	h := HTML{Out: &out}
+	h.RenderDocument(d)
+
+	for _, e := range []string{"javascript:", "data:", "<img"} {
+		if strings.Contains(out.String(), e) {
+			t.Errorf("Expected output not to contain %q, got=%v", e, out.String())
+		}
+	}
+	for _, e := range []string{`<span class="link">click</span>`, "logo"} {
+		if !strings.Contains(out.String(), e) {
+			t.Errorf("Expected output to contain %q, got=%v", e, out.String())
+		}
+	}
+}
+
+func TestHTML_RenderDocument_theme(t *testing.T) {
+	defer console.SetTheme("dark")
+
+	tests := []struct {
+		theme  string
+		dark   bool
+		expect []string
+	}{
+		{"dark", true, []string{"--bg: #1e1e1e", "img { max-width: 100%; }", ".link { color: #008000; font-weight: bold; text-decoration: underline; }", ".italic { font-style: italic; }"}},
+		{"light", false, []string{"--bg: #ffffff", ".link { color: #000080; font-weight: bold; text-decoration: underline; }"}},
+	}
+
+	for idx, tt := range tests {
+		if err := console.SetTheme(tt.theme); err != nil {
+			t.Fatal(err)
+		}
+
+		var out bytes.Buffer
+		h := HTML{Out: &out, Dark: tt.dark}
+		h.RenderDocument(testDoc)
+
+		for _, e := range tt.expect {
+			if !strings.Contains(out.String(), e) {
+				t.Errorf("[%d] Expected output to contain %q", idx, e)
+			}
+		}
+	}
+}
+
+func TestLinkDestination(t *testing.T) {
+	tests := []struct {
+		source string
+		link   string
+		expect string
+	}{
+		{"https://example.com/docs/README.md", "guide.md", "https://example.com/docs/guide.md"},
+		{"https://example.com/docs/README.md", "#usage", "#usage"},
+		{"https://example.com/docs/README.md", "mailto:kyle@example.com", "mailto:kyle@example.com"},
+		{"/home/kyle/README.md", "docs/guide.md", "docs/guide.md"},
+		{"github.com/KyleBanks/kurz@main/README.md", "docs/guide.md", "docs/guide.md"},
+		{"https://example.com/docs/README.md", "javascript:alert(1)", ""},
+		{"https://example.com/docs/README.md", "JavaScript:alert(1)", ""},
+		{"https://example.com/docs/README.md", "data:text/html;base64,PHNjcmlwdD4=", ""},
+		{"https://example.com/docs/README.md", " javascript:alert(1)", ""},
+	}
+
+	for idx, tt := range tests {
+		if got := linkDestination(tt.source, tt.link); got != tt.expect {
+			t.Errorf("[%d] Unexpected destination, expected=%v, got=%v", idx, tt.expect, got)
+		}
+	}
+}
+
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) {
+	return 0, errors.New("closed")
+}
+
+func TestHTML_Err(t *testing.T) {
+	h := HTML{Out: errWriter{}}
+	h.RenderDocument(testDoc)
+
+	if h.Err() == nil {
+		t.Error("Expected an error")
+	}
+}
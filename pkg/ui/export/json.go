@@ -0,0 +1,166 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+)
+
+// SchemaVersion is the version of the JSON schema written by JSON. It is
+// incremented whenever a field is removed or its meaning changes, while
+// fields may be added without changing the version.
+const SchemaVersion = 1
+
+// JSONDocument is the root object written by JSON.
+type JSONDocument struct {
+	Version int          `json:"version"`
+	Title   string       `json:"title"`
+	Source  string       `json:"source"`
+	Headers []JSONHeader `json:"headers"`
+}
+
+// JSONHeader is a header of the document and the sections beneath it.
+type JSONHeader struct {
+	Title    string        `json:"title"`
+	Level    int           `json:"level"`
+	Anchor   string        `json:"anchor,omitempty"`
+	Preamble bool          `json:"preamble,omitempty"`
+	Sections []JSONSection `json:"sections"`
+}
+
+// JSONSection is a block of content, such as a paragraph or code block.
+//
+// Kind is one of paragraph, list, code, quote, table or rule, and Text is
+// the plain text of the section as it is displayed, including the
+// destinations of links.
+type JSONSection struct {
+	Kind  string     `json:"kind"`
+	Lang  string     `json:"lang,omitempty"`
+	Text  string     `json:"text"`
+	Links []JSONLink `json:"links,omitempty"`
+	Table *JSONTable `json:"table,omitempty"`
+}
+
+// JSONLink is a link or image within the text of a section. The links of
+// a section are ordered by their Start.
+//
+// Start and End are the byte offsets of the link within the section's
+// Text, including its destination.
+type JSONLink struct {
+	Destination string `json:"destination"`
+	Start       int    `json:"start"`
+	End         int    `json:"end"`
+}
+
+// JSONTable is the content of a table section.
+//
+// Align contains the alignment of each column, which is one of left,
+// center or right.
+type JSONTable struct {
+	Align []string       `json:"align"`
+	Rows  []JSONTableRow `json:"rows"`
+}
+
+// JSONTableRow is a single row of a table, containing the plain text of
+// each cell.
+type JSONTableRow struct {
+	Header bool     `json:"header,omitempty"`
+	Cells  []string `json:"cells"`
+}
+
+var alignmentNames = map[doc.Alignment]string{
+	doc.AlignLeft:   "left",
+	doc.AlignCenter: "center",
+	doc.AlignRight:  "right",
+}
+
+// JSON renders documents as indented JSON, describing the structure of
+// the parsed document. See JSONDocument.
+type JSON struct {
+	Out io.Writer
+
+	err error
+}
+
+// RenderDocument writes the document to the output. Any error that occurs
+// while writing is available from Err.
+func (j *JSON) RenderDocument(d doc.Document) {
+	enc := json.NewEncoder(j.Out)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(NewJSONDocument(d)); err != nil && j.err == nil {
+		j.err = err
+	}
+}
+
+// Err returns the first error that occurred while writing.
+func (j *JSON) Err() error {
+	return j.err
+}
+
+// NewJSONDocument returns the JSON representation of a document.
+func NewJSONDocument(d doc.Document) JSONDocument {
+	jd := JSONDocument{
+		Version: SchemaVersion,
+		Title:   d.Title,
+		Source:  d.Source,
+		Headers: make([]JSONHeader, len(d.Headers)),
+	}
+
+	for i, h := range d.Headers {
+		jh := JSONHeader{
+			Title:    h.Title,
+			Level:    h.Level,
+			Anchor:   h.Anchor,
+			Preamble: h.Preamble,
+			Sections: make([]JSONSection, len(h.Content)),
+		}
+		for j, s := range h.Content {
+			jh.Sections[j] = newJSONSection(s)
+		}
+		jd.Headers[i] = jh
+	}
+	return jd
+}
+
+func newJSONSection(s doc.Section) JSONSection {
+	js := JSONSection{
+		Kind: s.Kind.String(),
+		Lang: s.Lang,
+		Text: s.Text(),
+	}
+
+	for _, l := range s.Links() {
+		js.Links = append(js.Links, JSONLink{
+			Destination: l.Destination,
+			Start:       l.Start,
+			End:         l.End,
+		})
+	}
+	// Images nested within links are numbered after the link, despite
+	// appearing before its destination.
+	sort.SliceStable(js.Links, func(i, k int) bool {
+		return js.Links[i].Start < js.Links[k].Start
+	})
+
+	if s.Table != nil {
+		js.Table = &JSONTable{
+			Align: make([]string, len(s.Table.Align)),
+			Rows:  make([]JSONTableRow, len(s.Table.Rows)),
+		}
+		for i, a := range s.Table.Align {
+			js.Table.Align[i] = alignmentNames[a]
+		}
+		for i, r := range s.Table.Rows {
+			row := JSONTableRow{Header: r.Header, Cells: make([]string, len(r.Cells))}
+			for c, spans := range r.Cells {
+				row.Cells[c] = strings.TrimSuffix(doc.Section{Spans: spans}.Text(), "\n")
+			}
+			js.Table.Rows[i] = row
+		}
+	}
+	return js
+}
@@ -0,0 +1,75 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestJSON_RenderDocument(t *testing.T) {
+	var out bytes.Buffer
+	j := JSON{Out: &out}
+	j.RenderDocument(testDoc)
+	if j.Err() != nil {
+		t.Fatalf("Unexpected error, got=%v", j.Err())
+	}
+
+	var got JSONDocument
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := JSONDocument{
+		Version: SchemaVersion,
+		Title:   "README.md",
+		Source:  "https://example.com/docs/README.md",
+		Headers: []JSONHeader{
+			{Title: "kurz", Preamble: true, Sections: []JSONSection{
+				{Kind: "paragraph", Text: "Intro <text>.\n"},
+			}},
+			{Title: "Usage", Level: 2, Anchor: "usage", Sections: []JSONSection{
+				{Kind: "paragraph", Text: "See the guide <guide.md> or usage <#usage>.\n", Links: []JSONLink{
+					{Destination: "guide.md", Start: 8, End: 24},
+					{Destination: "#usage", Start: 28, End: 42},
+				}},
+				{Kind: "code", Text: "func main() {}\n"},
+				{Kind: "table", Text: "Flag    │ Default\n────────┼────────\n--width │       0\n", Table: &JSONTable{
+					Align: []string{"left", "right"},
+					Rows: []JSONTableRow{
+						{Header: true, Cells: []string{"Flag", "Default"}},
+						{Cells: []string{"--width", "0"}},
+					},
+				}},
+				{Kind: "rule", Text: "────────────────────────────────────────\n"},
+			}},
+			{Title: "Options", Level: 3, Anchor: "options", Sections: []JSONSection{}},
+		},
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("Unexpected document, expected=%+v, got=%+v", expect, got)
+	}
+}
+
+func TestJSON_RenderDocument_format(t *testing.T) {
+	var out bytes.Buffer
+	j := JSON{Out: &out}
+	j.RenderDocument(testDoc)
+
+	// Empty lists are written as arrays rather than null, and HTML
+	// characters are not escaped.
+	for _, e := range []string{`"sections": []`, `"text": "Intro <text>.\n"`, "\n  \"version\": 1,\n"} {
+		if !bytes.Contains(out.Bytes(), []byte(e)) {
+			t.Errorf("Expected output to contain %q, got=%v", e, out.String())
+		}
+	}
+}
+
+func TestJSON_Err(t *testing.T) {
+	j := JSON{Out: errWriter{}}
+	j.RenderDocument(testDoc)
+
+	if j.Err() == nil {
+		t.Error("Expected an error")
+	}
+}
@@ -0,0 +1,100 @@
+// Package printer provides a ui.Canvas that writes documents to an output
+// stream as text, allowing them to be piped to other programs.
+package printer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+)
+
+// Printer renders documents as text, styled with ANSI escape codes
+// if color is enabled.
+type Printer struct {
+	Out io.Writer
+
+	// Width is the number of columns that lines are wrapped to,
+	// or zero if lines should not be wrapped.
+	Width int
+
+	// Color enables ANSI escape codes in the output.
+	Color bool
+
+	err error
+}
+
+// New returns a Printer that writes to the file, enabling color and
+// wrapping to the terminal width when the file is a terminal.
+//
+// When the file is not a terminal, lines are wrapped to the COLUMNS
+// environment variable if it is set.
+func New(f *os.File) *Printer {
+	p := Printer{
+		Out: f,
+	}
+
+	if IsTerminal(f) {
+		p.Color = true
+		p.Width, _ = terminalWidth(f)
+	}
+	if p.Width <= 0 {
+		p.Width, _ = strconv.Atoi(os.Getenv("COLUMNS"))
+	}
+
+	return &p
+}
+
+// RenderDocument writes the document to the output. Any error that occurs
+// while writing is available from Err.
+func (p *Printer) RenderDocument(d doc.Document) {
+	w := bufio.NewWriter(p.Out)
+	st := Styler{Color: p.Color}
+
+	d = d.Fit(p.Width)
+	singlePage := len(d.Headers) == 1 && d.Headers[0].Preamble
+	for i, h := range d.Headers {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if !singlePage {
+			title := strings.Repeat("#", h.Level) + " " + h.Title
+			if h.Preamble {
+				title = h.Title
+			}
+			fmt.Fprintln(w, st.Style(title, doc.Bold))
+			fmt.Fprintln(w)
+		}
+
+		for j, s := range h.Content {
+			if j > 0 {
+				fmt.Fprintln(w)
+			}
+			p.writeSection(w, s.Render(st))
+		}
+	}
+
+	if err := w.Flush(); err != nil && p.err == nil {
+		p.err = err
+	}
+}
+
+// Err returns the first error that occurred while writing.
+func (p *Printer) Err() error {
+	return p.err
+}
+
+// writeSection writes the rendered text of a section, wrapping each line
+// to the Printer's width.
+func (p *Printer) writeSection(w io.Writer, text string) {
+	text = strings.TrimSuffix(text, "\n")
+	for _, line := range strings.Split(text, "\n") {
+		for _, l := range wrap(line, p.Width) {
+			fmt.Fprintln(w, l)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package printer
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+)
+
+var testDoc = doc.Document{
+	Headers: []doc.Header{
+		{Title: "kurz", Preamble: true, Content: []doc.Section{
+			{Spans: []doc.Span{{Text: "Intro text.\n"}}},
+		}},
+		{Title: "Usage", Level: 2, Content: []doc.Section{
+			{Spans: []doc.Span{{Text: "Run it with "}, {Text: "kurz", Style: doc.Code}, {Text: " and a path.\n"}}},
+			{Kind: doc.CodeSection, Spans: []doc.Span{{Text: "$ kurz README.md", Style: doc.CodeBlock}}},
+		}},
+	},
+}
+
+func TestPrinter_RenderDocument(t *testing.T) {
+	var out bytes.Buffer
+	p := Printer{Out: &out}
+	p.RenderDocument(testDoc)
+
+	expect := "kurz\n\nIntro text.\n\n## Usage\n\nRun it with kurz and a path.\n\n   $ kurz README.md\n"
+	if out.String() != expect {
+		t.Errorf("Unexpected output, expected=%q, got=%q", expect, out.String())
+	}
+	if p.Err() != nil {
+		t.Errorf("Unexpected error, got=%v", p.Err())
+	}
+
+	// Single page documents are printed without a title
+	out.Reset()
+	p.RenderDocument(doc.Document{Headers: testDoc.Headers[:1]})
+	if expect := "Intro text.\n"; out.String() != expect {
+		t.Errorf("Unexpected single page output, expected=%q, got=%q", expect, out.String())
+	}
+}
+
+func TestPrinter_RenderDocument_wrap(t *testing.T) {
+	var out bytes.Buffer
+	p := Printer{Out: &out, Width: 16}
+	p.RenderDocument(doc.Document{Headers: testDoc.Headers[1:]})
+
+	expect := "## Usage\n\nRun it with kurz\nand a path.\n\n   $ kurz\n   README.md\n"
+	if out.String() != expect {
+		t.Errorf("Unexpected output, expected=%q, got=%q", expect, out.String())
+	}
+}
+
+func TestPrinter_RenderDocument_color(t *testing.T) {
+	var out bytes.Buffer
+	p := Printer{Out: &out, Color: true}
+	p.RenderDocument(doc.Document{Headers: []doc.Header{
+		{Title: "kurz", Preamble: true, Content: []doc.Section{
+			{Spans: []doc.Span{{Text: "Some "}, {Text: "bold", Style: doc.Bold}}},
+		}},
+	}})
+
+	expect := "Some \x1b[1mbold\x1b[0m\n"
+	if out.String() != expect {
+		t.Errorf("Unexpected output, expected=%q, got=%q", expect, out.String())
+	}
+}
+
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) {
+	return 0, errors.New("closed")
+}
+
+func TestPrinter_Err(t *testing.T) {
+	p := Printer{Out: errWriter{}}
+	p.RenderDocument(testDoc)
+
+	if p.Err() == nil {
+		t.Error("Expected a write error")
+	}
+}
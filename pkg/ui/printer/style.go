@@ -0,0 +1,96 @@
+package printer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/KyleBanks/kurz/pkg/doc"
+	"github.com/KyleBanks/kurz/pkg/ui/console"
+
+	"github.com/gdamore/tcell"
+)
+
+// reset clears all ANSI styling.
+const reset = "\x1b[0m"
+
+// textStyles maps the text style flags used by console.StyleMap to
+// their ANSI codes.
+var textStyles = map[rune]string{
+	'b': "1",
+	'd': "2",
+	'l': "5",
+	'r': "7",
+	'u': "4",
+}
+
+// Styler implements a doc.Styler that uses ANSI escape codes, applying
+// the same styles as the console.
+type Styler struct {
+	// Color enables ANSI escape codes. When it is not set, text is
+	// returned without styling.
+	Color bool
+}
+
+// Style returns the text surrounded by the ANSI codes of the Style.
+func (s Styler) Style(str string, ds doc.Style) string {
+	if !s.Color || str == "" {
+		return str
+	}
+
+	codes := ansiCodes(console.StyleMap[ds])
+	if codes == "" {
+		return str
+	}
+	return "\x1b[" + codes + "m" + str + reset
+}
+
+// Indent returns the indentation of the Style.
+func (s Styler) Indent(ds doc.Style) string {
+	return console.StyleMap[ds].Indent
+}
+
+// ansiCodes returns the semicolon separated ANSI codes of a console Style.
+func ansiCodes(st console.Style) string {
+	var codes []string
+	for _, r := range st.TextStyle {
+		if c, ok := textStyles[r]; ok {
+			codes = append(codes, c)
+		}
+	}
+	if c := colorCode(st.FgColor, false); c != "" {
+		codes = append(codes, c)
+	}
+	if c := colorCode(st.BgColor, true); c != "" {
+		codes = append(codes, c)
+	}
+	return strings.Join(codes, ";")
+}
+
+// colorCode returns the ANSI code of a color name, using the standard
+// codes for the 16 basic colors and 24-bit codes for all others.
+func colorCode(name string, background bool) string {
+	if name == "" {
+		return ""
+	}
+
+	c := tcell.GetColor(name)
+	base := 30
+	if background {
+		base = 40
+	}
+
+	switch {
+	case c == tcell.ColorDefault:
+		return ""
+	case c < 8:
+		return fmt.Sprintf("%d", base+int(c))
+	case c < 16:
+		return fmt.Sprintf("%d", base+60+int(c-8))
+	}
+
+	r, g, b := c.RGB()
+	if r < 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d;2;%d;%d;%d", base+8, r, g, b)
+}
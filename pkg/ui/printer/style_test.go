@@ -0,0 +1,27 @@
+package printer
+
+import (
+	"testing"
+
+	"github.com/KyleBanks/kurz/pkg/ui/console"
+)
+
+func TestAnsiCodes(t *testing.T) {
+	tests := []struct {
+		st     console.Style
+		expect string
+	}{
+		{console.Style{}, ""},
+		{console.Style{TextStyle: "b"}, "1"},
+		{console.Style{FgColor: "green", TextStyle: "bu"}, "1;4;32"},
+		{console.Style{FgColor: "red", BgColor: "yellow"}, "91;103"},
+		{console.Style{FgColor: "#9331ee"}, "38;2;147;49;238"},
+		{console.Style{FgColor: "not a color"}, ""},
+	}
+
+	for idx, tt := range tests {
+		if got := ansiCodes(tt.st); got != tt.expect {
+			t.Errorf("[%d] Unexpected codes, expected=%v, got=%v", idx, tt.expect, got)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package printer
+
+import (
+	"os"
+)
+
+// IsTerminal returns true if the file is a terminal rather than a
+// pipe or regular file.
+func IsTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
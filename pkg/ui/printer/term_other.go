@@ -0,0 +1,15 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+// +build !linux,!darwin,!dragonfly,!freebsd,!netbsd,!openbsd
+
+package printer
+
+import (
+	"errors"
+	"os"
+)
+
+// terminalWidth is not supported on this platform, so the width is
+// determined by the COLUMNS environment variable instead.
+func terminalWidth(f *os.File) (int, error) {
+	return 0, errors.New("terminal width is not supported on this platform")
+}
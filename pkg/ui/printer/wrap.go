@@ -0,0 +1,115 @@
+package printer
+
+import (
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// wrap splits a line into lines no wider than the width, breaking at
+// spaces where possible. ANSI escape codes are not counted towards the
+// width, and continuation lines keep the indentation of the first line.
+//
+// Styling is not carried over to continuation lines, so each line is
+// reset and then restyled with the escape codes that preceded the break.
+func wrap(line string, width int) []string {
+	indent := line[:len(line)-len(strings.TrimLeft(line, " "))]
+	if width <= 0 || width <= len(indent) || visibleWidth(line) <= width {
+		return []string{line}
+	}
+
+	var lines []string
+	var cur strings.Builder
+	var codes string // the escape codes in effect at the current position
+	col := 0
+	lastSpace, lastSpaceCodes := -1, ""
+
+	for i := 0; i < len(line); {
+		if code := escapeAt(line, i); code != "" {
+			cur.WriteString(code)
+			if code == reset {
+				codes = ""
+			} else {
+				codes += code
+			}
+			i += len(code)
+			continue
+		}
+
+		r, size := runeAt(line, i)
+		w := runewidth.RuneWidth(r)
+		if col+w > width && col > len(indent) {
+			text := cur.String()
+			rest := ""
+			restCodes := codes
+			if r == ' ' {
+				// Break at the space itself, dropping it.
+				i += size
+			} else if lastSpace > 0 {
+				rest = text[lastSpace+1:]
+				text = text[:lastSpace]
+				restCodes = lastSpaceCodes
+			}
+			if codes != "" || restCodes != "" {
+				text += reset
+			}
+			lines = append(lines, text)
+
+			cur.Reset()
+			cur.WriteString(indent + restCodes + rest)
+			col = len(indent) + visibleWidth(rest)
+			lastSpace = -1
+			if r == ' ' {
+				continue
+			}
+		}
+
+		if r == ' ' && col > len(indent) {
+			lastSpace, lastSpaceCodes = cur.Len(), codes
+		}
+		cur.WriteString(line[i : i+size])
+		col += w
+		i += size
+	}
+
+	return append(lines, cur.String())
+}
+
+// visibleWidth returns the display width of a string, excluding
+// ANSI escape codes.
+func visibleWidth(s string) int {
+	var width int
+	for i := 0; i < len(s); {
+		if code := escapeAt(s, i); code != "" {
+			i += len(code)
+			continue
+		}
+
+		r, size := runeAt(s, i)
+		width += runewidth.RuneWidth(r)
+		i += size
+	}
+	return width
+}
+
+// escapeAt returns the ANSI escape code that starts at the index of the
+// string, or an empty string if there is none.
+func escapeAt(s string, i int) string {
+	if !strings.HasPrefix(s[i:], "\x1b[") {
+		return ""
+	}
+
+	end := strings.IndexByte(s[i:], 'm')
+	if end < 0 {
+		return ""
+	}
+	return s[i : i+end+1]
+}
+
+// runeAt returns the rune starting at the index of the string.
+func runeAt(s string, i int) (rune, int) {
+	for _, r := range s[i:] {
+		return r, len(string(r))
+	}
+	return 0, 1
+}
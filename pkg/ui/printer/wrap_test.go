@@ -0,0 +1,31 @@
+package printer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		line   string
+		width  int
+		expect []string
+	}{
+		{"short line", 0, []string{"short line"}},
+		{"short line", 20, []string{"short line"}},
+		{"a line that needs wrapping", 10, []string{"a line", "that needs", "wrapping"}},
+		{"   indented text here", 12, []string{"   indented", "   text here"}},
+		{"averyveryverylongword", 8, []string{"averyver", "yverylon", "gword"}},
+		{
+			"plain \x1b[1mbold text\x1b[0m end",
+			10,
+			[]string{"plain \x1b[1mbold\x1b[0m", "\x1b[1mtext\x1b[0m end"},
+		},
+	}
+
+	for idx, tt := range tests {
+		if got := wrap(tt.line, tt.width); !reflect.DeepEqual(got, tt.expect) {
+			t.Errorf("[%d] Unexpected lines, expected=%q, got=%q", idx, tt.expect, got)
+		}
+	}
+}